@@ -3,311 +3,1239 @@ package smpp
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
+	"fmt"
 	"io"
-	"strings"
 )
 
-// Decoder decodes smpp pdu
+// ErrFieldTooLong reports c-octet string exceeding spec max length
+var ErrFieldTooLong = errors.New("field exceeds max length")
+
+// ErrFieldUnterminated reports c-octet string without terminator
+var ErrFieldUnterminated = errors.New("field is not null terminated")
+
+// ErrUnexpectedOctets reports octets left after pdu body
+var ErrUnexpectedOctets = errors.New("unexpected octets after pdu body")
+
+// DecodeMode selects how decoder treats spec deviations
+type DecodeMode int
+
+const (
+	// DecodeStrict rejects pdus violating field limits
+	DecodeStrict DecodeMode = iota
+	// DecodeLenient tolerates common deviations recording them in Header.Warnings
+	DecodeLenient
+)
+
+// FieldError reports malformed pdu field and its offset from pdu start
+type FieldError struct {
+	Field  string
+	Offset uint32
+	Err    error
+}
+
+// Error implements error
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s at offset %d: %v", e.Field, e.Offset, e.Err)
+}
+
+// Unwrap returns underlying error
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Decoder decodes smpp pdu, source buffer may hold several pdus
+// decoded one by one
 type Decoder struct {
-	r *bytes.Buffer
+	src      *bytes.Buffer
+	r        *bytes.Buffer
+	body     bytes.Buffer
+	input    bytes.Buffer
+	stream   *Reader
+	mode     DecodeMode
+	profile  *Profile
+	registry *Registry
+	header   *Header
+	start    int
+	// trace collects end offsets of fields read when tracing for HexDump
+	tracing bool
+	trace   []uint32
 }
 
 // NewDecoder constructs Decoder
 func NewDecoder(r *bytes.Buffer) *Decoder {
-	return &Decoder{r: r}
+	return &Decoder{src: r, r: r, profile: Profile50, registry: DefaultRegistry}
 }
 
-// readInt reads smpp integer
-func (d *Decoder) readInt(v *uint32) error {
-	b := make([]byte, 4)
-	n, err := d.r.Read(b)
+// NewStreamDecoder constructs Decoder reading pdus one by one from stream
+func NewStreamDecoder(stream *Reader) *Decoder {
+	r := new(bytes.Buffer)
+	return &Decoder{src: r, r: r, stream: stream, profile: Profile50, registry: DefaultRegistry}
+}
+
+// Reset discards decoder state and switches it to r, so decoders can be pooled
+func (d *Decoder) Reset(r *bytes.Buffer) {
+	d.src = r
+	d.r = r
+	d.body.Reset()
+	d.stream = nil
+	d.mode = DecodeStrict
+	d.profile = Profile50
+	d.registry = DefaultRegistry
+	d.header = nil
+	d.start = 0
+	d.tracing = false
+	d.trace = d.trace[:0]
+}
+
+// ResetBytes resets decoder to read pdus from b copied into reused input buffer
+func (d *Decoder) ResetBytes(b []byte) {
+	d.input.Reset()
+	d.input.Write(b)
+	d.Reset(&d.input)
+}
+
+// SetMode selects strict or lenient decoding
+func (d *Decoder) SetMode(mode DecodeMode) {
+	d.mode = mode
+}
+
+// Mode returns decoding mode
+func (d *Decoder) Mode() DecodeMode {
+	return d.mode
+}
+
+// fieldError reports err of field starting at current offset
+func (d *Decoder) fieldError(field string, err error) error {
+	return &FieldError{Field: field, Offset: d.consumed(), Err: err}
+}
+
+// warn records deviation tolerated by lenient decoding
+func (d *Decoder) warn(field string, offset uint32, err error) {
+	d.header.Warnings = append(d.header.Warnings, &FieldError{Field: field, Offset: offset, Err: err})
+}
+
+// SetRegistry selects registry used for custom pdus
+func (d *Decoder) SetRegistry(registry *Registry) {
+	d.registry = registry
+}
+
+// SetVersion selects interface version profile used for decoding
+func (d *Decoder) SetVersion(version uint32) {
+	d.profile = ProfileFor(version)
+}
+
+// Version returns interface version used for decoding
+func (d *Decoder) Version() uint32 {
+	return d.profile.Version
+}
+
+// consumed returns number of bytes read from current pdu
+func (d *Decoder) consumed() uint32 {
+	return uint32(d.start - d.r.Len())
+}
+
+// bodyless reports whether current pdu has no body, as responses with error status may
+func (d *Decoder) bodyless() bool {
+	return d.header.CommandLength <= PduHeaderLength
+}
+
+// readRest reads unread bytes of current pdu
+func (d *Decoder) readRest(v *[]byte) error {
+	c := d.consumed()
+	if d.header.CommandLength < c {
+		return ErrEsmeRinvCmdLen
+	}
+	return d.readOctets(v, d.header.CommandLength-c)
+}
+
+// skip discards unread bytes of current pdu
+func (d *Decoder) skip() {
+	if c := d.consumed(); d.header.CommandLength > c {
+		d.r.Next(int(d.header.CommandLength - c))
+	}
+}
+
+// mark records end offset of field just read when tracing
+func (d *Decoder) mark() {
+	if d.tracing {
+		d.trace = append(d.trace, d.consumed())
+	}
+}
+
+// readInt8 reads smpp 1 octet integer
+func (d *Decoder) readInt8(v *uint32) error {
+	b, err := d.r.ReadByte()
 	if err != nil {
 		return err
 	}
-	if n < len(b) {
+	*v = uint32(b)
+	d.mark()
+	return nil
+}
+
+// readInt16 reads smpp 2 octet integer
+func (d *Decoder) readInt16(v *uint32) error {
+	if d.r.Len() < 2 {
 		return io.EOF
 	}
+	b := d.r.Next(2)
+	*v = uint32(binary.BigEndian.Uint16(b))
+	d.mark()
+	return nil
+}
+
+// readInt32 reads smpp 4 octet integer
+func (d *Decoder) readInt32(v *uint32) error {
+	if d.r.Len() < 4 {
+		return io.EOF
+	}
+	b := d.r.Next(4)
 	*v = binary.BigEndian.Uint32(b)
+	d.mark()
 	return nil
 }
 
-// readString reads smpp octet string
-func (d *Decoder) readString(v *string, length uint32) error {
-	w := &strings.Builder{}
-	for i := uint32(0); i < length; i++ {
-		b, err := d.r.ReadByte()
-		if err != nil {
-			return err
-		}
-		if b == byte(0) {
-			break
+// readString reads smpp c-octet string of max length including terminator,
+// v is kept when unchanged to spare allocation
+func (d *Decoder) readString(v *string, length uint32, field string, status error) error {
+	offset := d.consumed()
+	b := d.r.Bytes()
+	n := bytes.IndexByte(b, 0)
+	switch {
+	case n >= 0 && uint32(n) < length:
+		d.r.Next(n + 1)
+	case d.mode != DecodeLenient:
+		return &FieldError{Field: field, Offset: offset, Err: status}
+	case n >= 0:
+		d.warn(field, offset, ErrFieldTooLong)
+		d.r.Next(n + 1)
+	default:
+		d.warn(field, offset, ErrFieldUnterminated)
+		n = len(b)
+		d.r.Next(n)
+	}
+	b = b[:n]
+	if string(b) != *v {
+		*v = string(b)
+	}
+	d.mark()
+	return nil
+}
+
+// readOctets reads smpp octet string of exact length, reusing capacity of v
+func (d *Decoder) readOctets(v *[]byte, length uint32) error {
+	if uint32(d.r.Len()) < length {
+		return io.EOF
+	}
+	b := d.r.Next(int(length))
+	*v = append((*v)[:0], b...)
+	d.mark()
+	return nil
+}
+
+// readShortMessage reads sm_length and short_message cross checking sm_length against
+// remaining body octets, which are tlvs when pdu carries them or nothing otherwise,
+// lenient decoding takes remaining octets as short message on mismatch
+func (d *Decoder) readShortMessage(smLength *uint32, message *[]byte, tlvs bool) error {
+	offset := d.consumed()
+	if err := d.readInt8(smLength); err != nil {
+		return d.fieldError("sm_length", ErrEsmeRinvMsgLen)
+	}
+	rest := uint32(d.r.Len())
+	valid := *smLength <= rest
+	if valid && tlvs && d.profile.Tlv {
+		valid = tlvFramed(d.r.Bytes()[*smLength:])
+	} else if valid && !tlvs {
+		valid = *smLength == rest
+	}
+	length := *smLength
+	if !valid {
+		if d.mode != DecodeLenient {
+			return &FieldError{Field: "sm_length", Offset: offset, Err: ErrEsmeRinvMsgLen}
 		}
-		w.WriteByte(b)
+		d.warn("sm_length", offset, ErrEsmeRinvMsgLen)
+		length = rest
+	}
+	if err := d.readOctets(message, length); err != nil {
+		return d.fieldError("short_message", ErrEsmeRinvMsgLen)
 	}
-	*v = w.String()
 	return nil
 }
 
+// tlvFramed tells whether b splits into whole tlvs
+func tlvFramed(b []byte) bool {
+	for len(b) >= 4 {
+		length := 4 + int(binary.BigEndian.Uint16(b[2:]))
+		if len(b) < length {
+			return false
+		}
+		b = b[length:]
+	}
+	return len(b) == 0
+}
+
 // readHeader reads smpp pdu header
 func (d *Decoder) readHeader(header *Header) error {
-	if err := d.readInt(&header.CommandLength); err != nil {
+	header.Warnings = header.Warnings[:0]
+	if err := d.readInt32(&header.CommandLength); err != nil {
 		return ErrEsmeRinvCmdLen
 	}
-	if err := d.readInt(&header.CommandID); err != nil {
+	if err := d.readInt32(&header.CommandID); err != nil {
 		return ErrEsmeRinvCmdId
 	}
-	if err := d.readInt(&header.CommandStatus); err != nil {
+	if err := d.readInt32(&header.CommandStatus); err != nil {
 		return err
 	}
-	return d.readInt(&header.SequenceNumber)
+	if err := d.readInt32(&header.SequenceNumber); err != nil {
+		return err
+	}
+	if header.CommandLength < PduHeaderLength {
+		return &FrameError{
+			CommandLength:  header.CommandLength,
+			CommandID:      header.CommandID,
+			SequenceNumber: header.SequenceNumber,
+		}
+	}
+	return nil
 }
 
-// readTlvMap reads smpp tlv map
-func (d *Decoder) readTlvMap(tlvMap TlvMap) error {
+// readTlvList reads smpp tlv list
+func (d *Decoder) readTlvList(list *TlvList) error {
+	if !d.profile.Tlv {
+		d.skip()
+		return nil
+	}
 	for d.r.Len() > 0 {
-		tlv := new(Tlv)
-		if err := d.readInt(&tlv.Tag); err != nil {
-			return ErrEsmeRoptParNotAllwd
+		i := len(*list)
+		if i < cap(*list) {
+			*list = (*list)[:i+1]
+		} else {
+			*list = append(*list, Tlv{})
 		}
-		if err := d.readInt(&tlv.Length); err != nil {
-			return ErrEsmeRinvParLen
+		tlv := &(*list)[i]
+		offset := d.consumed()
+		if err := d.readInt16(&tlv.Tag); err != nil {
+			return &FieldError{Field: "tlv", Offset: offset, Err: ErrEsmeRinvCmdLen}
 		}
-		if err := d.readString(&tlv.Value, tlv.Length); err != nil {
-			return ErrEsmeRinvOptParamVal
+		if err := d.readInt16(&tlv.Length); err != nil {
+			return &FieldError{Field: TlvName(tlv.Tag), Offset: offset, Err: ErrEsmeRinvParLen}
+		}
+		if err := d.readOctets(&tlv.Value, tlv.Length); err != nil {
+			if d.mode == DecodeLenient {
+				d.warn(TlvName(tlv.Tag), offset, ErrEsmeRinvParLen)
+				*list = (*list)[:i]
+				d.skip()
+				return nil
+			}
+			return &FieldError{Field: TlvName(tlv.Tag), Offset: offset, Err: ErrEsmeRinvOptParamVal}
+		}
+		if err := tlv.validate(); err != nil {
+			if d.mode != DecodeLenient {
+				return &FieldError{Field: TlvName(tlv.Tag), Offset: offset, Err: err}
+			}
+			d.warn(TlvName(tlv.Tag), offset, err)
 		}
-		tlvMap[TlvName(tlv.Tag)] = *tlv
 	}
 	return nil
 }
 
 // readBindBody reads smpp bind body
 func (d *Decoder) readBindBody(body *BindBody) error {
-	if err := d.readString(&body.SystemID, 16); err != nil {
-		return ErrEsmeRinvSysId
-	}
-	if err := d.readString(&body.Password, 9); err != nil {
-		return ErrEsmeRinvPaswd
-	}
-	if err := d.readString(&body.SystemType, 13); err != nil {
-		return ErrEsmeRinvSysTyp
-	}
-	if err := d.readInt(&body.InterfaceVersion); err != nil {
+	if err := d.readString(&body.SystemID, 16, "system_id", ErrEsmeRinvSysId); err != nil {
 		return err
 	}
-	if err := d.readInt(&body.AddrTon); err != nil {
+	if err := d.readString(&body.Password, 9, "password", ErrEsmeRinvPaswd); err != nil {
 		return err
 	}
-	if err := d.readInt(&body.AddrNpi); err != nil {
+	if err := d.readString(&body.SystemType, 13, "system_type", ErrEsmeRinvSysTyp); err != nil {
 		return err
 	}
-	return d.readString(&body.AddressRange, 41)
+	if err := d.readInt8(&body.InterfaceVersion); err != nil {
+		return d.fieldError("interface_version", err)
+	}
+	if err := d.readInt8(&body.AddrTon); err != nil {
+		return d.fieldError("addr_ton", err)
+	}
+	if err := d.readInt8(&body.AddrNpi); err != nil {
+		return d.fieldError("addr_npi", err)
+	}
+	return d.readString(&body.AddressRange, 41, "address_range", ErrEsmeRbindFail)
 }
 
 // readBindRespBody reads smpp bind resp
 func (d *Decoder) readBindRespBody(body *BindRespBody) error {
-	return d.readString(&body.SystemID, 16)
+	if d.bodyless() {
+		return nil
+	}
+	return d.readString(&body.SystemID, 16, "system_id", ErrEsmeRinvSysId)
 }
 
 // readOutBindBody reads smpp outbind body
 func (d *Decoder) readOutBindBody(body *OutBindBody) error {
-	if err := d.readString(&body.SystemID, 16); err != nil {
-		return ErrEsmeRinvSysId
+	if err := d.readString(&body.SystemID, 16, "system_id", ErrEsmeRinvSysId); err != nil {
+		return err
 	}
-	if err := d.readString(&body.Password, 9); err != nil {
-		return ErrEsmeRinvPaswd
+	if err := d.readString(&body.Password, 9, "password", ErrEsmeRinvPaswd); err != nil {
+		return err
 	}
 	return nil
 }
 
 // readSmBody reads smpp short message body
 func (d *Decoder) readSmBody(body *SmBody) error {
-	if err := d.readString(&body.ServiceType, 6); err != nil {
-		return ErrEsmeRinvSerTyp
+	if err := d.readString(&body.ServiceType, 6, "service_type", ErrEsmeRinvSerTyp); err != nil {
+		return err
 	}
-	if err := d.readInt(&body.SourceAddrTon); err != nil {
-		return ErrEsmeRinvSrcTon
+	if err := d.readInt8(&body.SourceAddrTon); err != nil {
+		return d.fieldError("source_addr_ton", ErrEsmeRinvSrcTon)
 	}
-	if err := d.readInt(&body.SourceAddrNpi); err != nil {
-		return ErrEsmeRinvSrcNpi
+	if err := d.readInt8(&body.SourceAddrNpi); err != nil {
+		return d.fieldError("source_addr_npi", ErrEsmeRinvSrcNpi)
 	}
-	if err := d.readString(&body.SourceAddr, 21); err != nil {
-		return ErrEsmeRinvSrcAdr
+	if err := d.readString(&body.SourceAddr, 21, "source_addr", ErrEsmeRinvSrcAdr); err != nil {
+		return err
 	}
-	if err := d.readInt(&body.DestAddrTon); err != nil {
-		return ErrEsmeRinvDstTon
+	if err := d.readInt8(&body.DestAddrTon); err != nil {
+		return d.fieldError("dest_addr_ton", ErrEsmeRinvDstTon)
 	}
-	if err := d.readInt(&body.DestAddrNpi); err != nil {
-		return ErrEsmeRinvDstNpi
+	if err := d.readInt8(&body.DestAddrNpi); err != nil {
+		return d.fieldError("dest_addr_npi", ErrEsmeRinvDstNpi)
 	}
-	if err := d.readString(&body.DestinationAddr, 21); err != nil {
-		return ErrEsmeRinvDstAdr
+	if err := d.readString(&body.DestinationAddr, 21, "destination_addr", ErrEsmeRinvDstAdr); err != nil {
+		return err
 	}
-	if err := d.readInt(&body.EsmClass); err != nil {
-		return ErrEsmeRinvEsmClass
+	if err := d.readInt8(&body.EsmClass); err != nil {
+		return d.fieldError("esm_class", ErrEsmeRinvEsmClass)
 	}
-	if err := d.readInt(&body.ProtocolID); err != nil {
-		return err
+	if err := d.readInt8(&body.ProtocolID); err != nil {
+		return d.fieldError("protocol_id", err)
 	}
-	if err := d.readInt(&body.PriorityFlag); err != nil {
-		return ErrEsmeRinvPrtFlg
+	if err := d.readInt8(&body.PriorityFlag); err != nil {
+		return d.fieldError("priority_flag", ErrEsmeRinvPrtFlg)
 	}
-	if err := d.readString(&body.ScheduleDeliveryTime, 17); err != nil {
-		return ErrEsmeRinvSched
+	if err := d.readString(&body.ScheduleDeliveryTime, 17, "schedule_delivery_time", ErrEsmeRinvSched); err != nil {
+		return err
 	}
-	if err := d.readString(&body.ValidityPeriod, 17); err != nil {
-		return ErrEsmeRinvExpiry
+	if err := d.readString(&body.ValidityPeriod, 17, "validity_period", ErrEsmeRinvExpiry); err != nil {
+		return err
 	}
-	if err := d.readInt(&body.RegisteredDelivery); err != nil {
-		return ErrEsmeRinvRegDlvFlg
+	if err := d.readInt8(&body.RegisteredDelivery); err != nil {
+		return d.fieldError("registered_delivery", ErrEsmeRinvRegDlvFlg)
 	}
-	if err := d.readInt(&body.ReplaceIfPresentFlag); err != nil {
-		return ErrEsmeRinvRepFlag
+	if err := d.readInt8(&body.ReplaceIfPresentFlag); err != nil {
+		return d.fieldError("replace_if_present_flag", ErrEsmeRinvRepFlag)
 	}
-	if err := d.readInt(&body.DataCoding); err != nil {
-		return ErrEsmeRinvDcs
+	if err := d.readInt8(&body.DataCoding); err != nil {
+		return d.fieldError("data_coding", ErrEsmeRinvDcs)
 	}
-	if err := d.readInt(&body.SmDefaultMessageID); err != nil {
-		return ErrEsmeRinvMsgId
+	if err := d.readInt8(&body.SmDefaultMessageID); err != nil {
+		return d.fieldError("sm_default_msg_id", ErrEsmeRinvMsgId)
 	}
-	if err := d.readInt(&body.SmLength); err != nil {
-		return ErrEsmeRinvMsgLen
+	if err := d.readShortMessage(&body.SmLength, &body.ShortMessage, true); err != nil {
+		return err
 	}
-	return d.readString(&body.ShortMessage, body.SmLength)
+	return nil
 }
 
 // readSmRespBody reads smpp message response
 func (d *Decoder) readSmRespBody(body *SmRespBody) error {
-	return d.readString(&body.MessageID, 65)
+	if d.bodyless() {
+		return nil
+	}
+	if d.profile.Version == InterfaceVersion33 {
+		// SMPP v3.3 smsc may omit message id terminator at the end of pdu
+		var b []byte
+		if err := d.readRest(&b); err != nil {
+			return err
+		}
+		if i := bytes.IndexByte(b, 0); i >= 0 {
+			b = b[:i]
+		}
+		body.MessageID = string(b)
+		return nil
+	}
+	return d.readString(&body.MessageID, 65, "message_id", ErrEsmeRinvMsgId)
+}
+
+// readQuerySmBody reads smpp query sm body
+func (d *Decoder) readQuerySmBody(body *QuerySmBody) error {
+	if err := d.readString(&body.MessageID, 65, "message_id", ErrEsmeRinvMsgId); err != nil {
+		return err
+	}
+	if err := d.readInt8(&body.SourceAddrTon); err != nil {
+		return d.fieldError("source_addr_ton", ErrEsmeRinvSrcTon)
+	}
+	if err := d.readInt8(&body.SourceAddrNpi); err != nil {
+		return d.fieldError("source_addr_npi", ErrEsmeRinvSrcNpi)
+	}
+	if err := d.readString(&body.SourceAddr, 21, "source_addr", ErrEsmeRinvSrcAdr); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readQuerySmRespBody reads smpp query sm resp body
+func (d *Decoder) readQuerySmRespBody(body *QuerySmRespBody) error {
+	if d.bodyless() {
+		return nil
+	}
+	if err := d.readString(&body.MessageID, 65, "message_id", ErrEsmeRinvMsgId); err != nil {
+		return err
+	}
+	if err := d.readString(&body.FinalDate, 17, "final_date", ErrEsmeRqueryFail); err != nil {
+		return err
+	}
+	if err := d.readInt8(&body.MessageState); err != nil {
+		return d.fieldError("message_state", ErrEsmeRqueryFail)
+	}
+	if err := d.readInt8(&body.ErrorCode); err != nil {
+		return d.fieldError("error_code", ErrEsmeRqueryFail)
+	}
+	return nil
+}
+
+// readReplaceSmBody reads smpp replace sm body
+func (d *Decoder) readReplaceSmBody(body *ReplaceSmBody) error {
+	if err := d.readString(&body.MessageID, 65, "message_id", ErrEsmeRinvMsgId); err != nil {
+		return err
+	}
+	if err := d.readInt8(&body.SourceAddrTon); err != nil {
+		return d.fieldError("source_addr_ton", ErrEsmeRinvSrcTon)
+	}
+	if err := d.readInt8(&body.SourceAddrNpi); err != nil {
+		return d.fieldError("source_addr_npi", ErrEsmeRinvSrcNpi)
+	}
+	if err := d.readString(&body.SourceAddr, 21, "source_addr", ErrEsmeRinvSrcAdr); err != nil {
+		return err
+	}
+	if err := d.readString(&body.ScheduleDeliveryTime, 17, "schedule_delivery_time", ErrEsmeRinvSched); err != nil {
+		return err
+	}
+	if err := d.readString(&body.ValidityPeriod, 17, "validity_period", ErrEsmeRinvExpiry); err != nil {
+		return err
+	}
+	if err := d.readInt8(&body.RegisteredDelivery); err != nil {
+		return d.fieldError("registered_delivery", ErrEsmeRinvRegDlvFlg)
+	}
+	if err := d.readInt8(&body.SmDefaultMessageID); err != nil {
+		return d.fieldError("sm_default_msg_id", ErrEsmeRinvDftMsgId)
+	}
+	if err := d.readShortMessage(&body.SmLength, &body.ShortMessage, false); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readCancelSmBody reads smpp cancel sm body
+func (d *Decoder) readCancelSmBody(body *CancelSmBody) error {
+	if err := d.readString(&body.ServiceType, 6, "service_type", ErrEsmeRinvSerTyp); err != nil {
+		return err
+	}
+	if err := d.readString(&body.MessageID, 65, "message_id", ErrEsmeRinvMsgId); err != nil {
+		return err
+	}
+	if err := d.readInt8(&body.SourceAddrTon); err != nil {
+		return d.fieldError("source_addr_ton", ErrEsmeRinvSrcTon)
+	}
+	if err := d.readInt8(&body.SourceAddrNpi); err != nil {
+		return d.fieldError("source_addr_npi", ErrEsmeRinvSrcNpi)
+	}
+	if err := d.readString(&body.SourceAddr, 21, "source_addr", ErrEsmeRinvSrcAdr); err != nil {
+		return err
+	}
+	if err := d.readInt8(&body.DestAddrTon); err != nil {
+		return d.fieldError("dest_addr_ton", ErrEsmeRinvDstTon)
+	}
+	if err := d.readInt8(&body.DestAddrNpi); err != nil {
+		return d.fieldError("dest_addr_npi", ErrEsmeRinvDstNpi)
+	}
+	if err := d.readString(&body.DestinationAddr, 21, "destination_addr", ErrEsmeRinvDstAdr); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readDestAddress reads smpp submit multi destination address
+func (d *Decoder) readDestAddress(addr *DestAddress) error {
+	if err := d.readInt8(&addr.DestFlag); err != nil {
+		return d.fieldError("dest_flag", ErrEsmeRinvDestFlag)
+	}
+	switch addr.DestFlag {
+	case DestFlagSme:
+		if err := d.readInt8(&addr.DestAddrTon); err != nil {
+			return d.fieldError("dest_addr_ton", ErrEsmeRinvDstTon)
+		}
+		if err := d.readInt8(&addr.DestAddrNpi); err != nil {
+			return d.fieldError("dest_addr_npi", ErrEsmeRinvDstNpi)
+		}
+		if err := d.readString(&addr.DestinationAddr, 21, "destination_addr", ErrEsmeRinvDstAdr); err != nil {
+			return err
+		}
+		addr.DlName = ""
+		return nil
+	case DestFlagDistlist:
+		if err := d.readString(&addr.DlName, 21, "dl_name", ErrEsmeRinvDlName); err != nil {
+			return err
+		}
+		addr.DestAddrTon, addr.DestAddrNpi, addr.DestinationAddr = 0, 0, ""
+		return nil
+	}
+	return ErrEsmeRinvDestFlag
+}
+
+// readSubmitMultiBody reads smpp submit multi body
+func (d *Decoder) readSubmitMultiBody(body *SubmitMultiBody) error {
+	if err := d.readString(&body.ServiceType, 6, "service_type", ErrEsmeRinvSerTyp); err != nil {
+		return err
+	}
+	if err := d.readInt8(&body.SourceAddrTon); err != nil {
+		return d.fieldError("source_addr_ton", ErrEsmeRinvSrcTon)
+	}
+	if err := d.readInt8(&body.SourceAddrNpi); err != nil {
+		return d.fieldError("source_addr_npi", ErrEsmeRinvSrcNpi)
+	}
+	if err := d.readString(&body.SourceAddr, 21, "source_addr", ErrEsmeRinvSrcAdr); err != nil {
+		return err
+	}
+	if err := d.readInt8(&body.NumberOfDests); err != nil {
+		return d.fieldError("number_of_dests", ErrEsmeRinvNumDests)
+	}
+	if body.NumberOfDests == 0 {
+		return ErrEsmeRinvNumDests
+	}
+	// backing array of previous decode is reused
+	if uint32(cap(body.DestAddresses)) < body.NumberOfDests {
+		body.DestAddresses = make([]DestAddress, body.NumberOfDests)
+	}
+	body.DestAddresses = body.DestAddresses[:body.NumberOfDests]
+	for i := range body.DestAddresses {
+		if err := d.readDestAddress(&body.DestAddresses[i]); err != nil {
+			return err
+		}
+	}
+	if err := d.readInt8(&body.EsmClass); err != nil {
+		return d.fieldError("esm_class", ErrEsmeRinvEsmClass)
+	}
+	if err := d.readInt8(&body.ProtocolID); err != nil {
+		return d.fieldError("protocol_id", err)
+	}
+	if err := d.readInt8(&body.PriorityFlag); err != nil {
+		return d.fieldError("priority_flag", ErrEsmeRinvPrtFlg)
+	}
+	if err := d.readString(&body.ScheduleDeliveryTime, 17, "schedule_delivery_time", ErrEsmeRinvSched); err != nil {
+		return err
+	}
+	if err := d.readString(&body.ValidityPeriod, 17, "validity_period", ErrEsmeRinvExpiry); err != nil {
+		return err
+	}
+	if err := d.readInt8(&body.RegisteredDelivery); err != nil {
+		return d.fieldError("registered_delivery", ErrEsmeRinvRegDlvFlg)
+	}
+	if err := d.readInt8(&body.ReplaceIfPresentFlag); err != nil {
+		return d.fieldError("replace_if_present_flag", ErrEsmeRinvRepFlag)
+	}
+	if err := d.readInt8(&body.DataCoding); err != nil {
+		return d.fieldError("data_coding", ErrEsmeRinvDcs)
+	}
+	if err := d.readInt8(&body.SmDefaultMessageID); err != nil {
+		return d.fieldError("sm_default_msg_id", ErrEsmeRinvDftMsgId)
+	}
+	if err := d.readShortMessage(&body.SmLength, &body.ShortMessage, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readSubmitMultiRespBody reads smpp submit multi resp body
+func (d *Decoder) readSubmitMultiRespBody(body *SubmitMultiRespBody) error {
+	if d.bodyless() {
+		return nil
+	}
+	if err := d.readString(&body.MessageID, 65, "message_id", ErrEsmeRinvMsgId); err != nil {
+		return err
+	}
+	if err := d.readInt8(&body.NoUnsuccess); err != nil {
+		return d.fieldError("no_unsuccess", ErrEsmeRinvNumDests)
+	}
+	// backing array of previous decode is reused
+	if uint32(cap(body.UnsuccessSmes)) < body.NoUnsuccess {
+		body.UnsuccessSmes = make([]UnsuccessSme, body.NoUnsuccess)
+	}
+	body.UnsuccessSmes = body.UnsuccessSmes[:body.NoUnsuccess]
+	for i := range body.UnsuccessSmes {
+		sme := &body.UnsuccessSmes[i]
+		if err := d.readInt8(&sme.DestAddrTon); err != nil {
+			return d.fieldError("dest_addr_ton", ErrEsmeRinvDstTon)
+		}
+		if err := d.readInt8(&sme.DestAddrNpi); err != nil {
+			return d.fieldError("dest_addr_npi", ErrEsmeRinvDstNpi)
+		}
+		if err := d.readString(&sme.DestinationAddr, 21, "destination_addr", ErrEsmeRinvDstAdr); err != nil {
+			return err
+		}
+		if err := d.readInt32(&sme.ErrorStatusCode); err != nil {
+			return d.fieldError("error_status_code", err)
+		}
+	}
+	return nil
+}
+
+// readDataSmBody reads smpp data sm body
+func (d *Decoder) readDataSmBody(body *DataSmBody) error {
+	if err := d.readString(&body.ServiceType, 6, "service_type", ErrEsmeRinvSerTyp); err != nil {
+		return err
+	}
+	if err := d.readInt8(&body.SourceAddrTon); err != nil {
+		return d.fieldError("source_addr_ton", ErrEsmeRinvSrcTon)
+	}
+	if err := d.readInt8(&body.SourceAddrNpi); err != nil {
+		return d.fieldError("source_addr_npi", ErrEsmeRinvSrcNpi)
+	}
+	if err := d.readString(&body.SourceAddr, 65, "source_addr", ErrEsmeRinvSrcAdr); err != nil {
+		return err
+	}
+	if err := d.readInt8(&body.DestAddrTon); err != nil {
+		return d.fieldError("dest_addr_ton", ErrEsmeRinvDstTon)
+	}
+	if err := d.readInt8(&body.DestAddrNpi); err != nil {
+		return d.fieldError("dest_addr_npi", ErrEsmeRinvDstNpi)
+	}
+	if err := d.readString(&body.DestinationAddr, 65, "destination_addr", ErrEsmeRinvDstAdr); err != nil {
+		return err
+	}
+	if err := d.readInt8(&body.EsmClass); err != nil {
+		return d.fieldError("esm_class", ErrEsmeRinvEsmClass)
+	}
+	if err := d.readInt8(&body.RegisteredDelivery); err != nil {
+		return d.fieldError("registered_delivery", ErrEsmeRinvRegDlvFlg)
+	}
+	if err := d.readInt8(&body.DataCoding); err != nil {
+		return d.fieldError("data_coding", ErrEsmeRinvDcs)
+	}
+	return nil
+}
+
+// readAlertNotificationBody reads smpp alert notification body
+func (d *Decoder) readAlertNotificationBody(body *AlertNotificationBody) error {
+	if err := d.readInt8(&body.SourceAddrTon); err != nil {
+		return d.fieldError("source_addr_ton", ErrEsmeRinvSrcTon)
+	}
+	if err := d.readInt8(&body.SourceAddrNpi); err != nil {
+		return d.fieldError("source_addr_npi", ErrEsmeRinvSrcNpi)
+	}
+	if err := d.readString(&body.SourceAddr, 65, "source_addr", ErrEsmeRinvSrcAdr); err != nil {
+		return err
+	}
+	if err := d.readInt8(&body.EsmeAddrTon); err != nil {
+		return d.fieldError("esme_addr_ton", ErrEsmeRinvDstTon)
+	}
+	if err := d.readInt8(&body.EsmeAddrNpi); err != nil {
+		return d.fieldError("esme_addr_npi", ErrEsmeRinvDstNpi)
+	}
+	if err := d.readString(&body.EsmeAddr, 65, "esme_addr", ErrEsmeRinvDstAdr); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readBroadcastSmBody reads smpp broadcast sm body
+func (d *Decoder) readBroadcastSmBody(body *BroadcastSmBody) error {
+	if err := d.readString(&body.ServiceType, 6, "service_type", ErrEsmeRinvSerTyp); err != nil {
+		return err
+	}
+	if err := d.readInt8(&body.SourceAddrTon); err != nil {
+		return d.fieldError("source_addr_ton", ErrEsmeRinvSrcTon)
+	}
+	if err := d.readInt8(&body.SourceAddrNpi); err != nil {
+		return d.fieldError("source_addr_npi", ErrEsmeRinvSrcNpi)
+	}
+	if err := d.readString(&body.SourceAddr, 21, "source_addr", ErrEsmeRinvSrcAdr); err != nil {
+		return err
+	}
+	if err := d.readString(&body.MessageID, 65, "message_id", ErrEsmeRinvMsgId); err != nil {
+		return err
+	}
+	if err := d.readInt8(&body.PriorityFlag); err != nil {
+		return d.fieldError("priority_flag", ErrEsmeRinvPrtFlg)
+	}
+	if err := d.readString(&body.ScheduleDeliveryTime, 17, "schedule_delivery_time", ErrEsmeRinvSched); err != nil {
+		return err
+	}
+	if err := d.readString(&body.ValidityPeriod, 17, "validity_period", ErrEsmeRinvExpiry); err != nil {
+		return err
+	}
+	if err := d.readInt8(&body.ReplaceIfPresentFlag); err != nil {
+		return d.fieldError("replace_if_present_flag", ErrEsmeRinvRepFlag)
+	}
+	if err := d.readInt8(&body.DataCoding); err != nil {
+		return d.fieldError("data_coding", ErrEsmeRinvDcs)
+	}
+	if err := d.readInt8(&body.SmDefaultMessageID); err != nil {
+		return d.fieldError("sm_default_msg_id", ErrEsmeRinvDftMsgId)
+	}
+	return nil
+}
+
+// readCancelBroadcastSmBody reads smpp cancel broadcast sm body
+func (d *Decoder) readCancelBroadcastSmBody(body *CancelBroadcastSmBody) error {
+	if err := d.readString(&body.ServiceType, 6, "service_type", ErrEsmeRinvSerTyp); err != nil {
+		return err
+	}
+	if err := d.readString(&body.MessageID, 65, "message_id", ErrEsmeRinvMsgId); err != nil {
+		return err
+	}
+	if err := d.readInt8(&body.SourceAddrTon); err != nil {
+		return d.fieldError("source_addr_ton", ErrEsmeRinvSrcTon)
+	}
+	if err := d.readInt8(&body.SourceAddrNpi); err != nil {
+		return d.fieldError("source_addr_npi", ErrEsmeRinvSrcNpi)
+	}
+	if err := d.readString(&body.SourceAddr, 21, "source_addr", ErrEsmeRinvSrcAdr); err != nil {
+		return err
+	}
+	return nil
 }
 
 // Decode decodes smpp pdu
-func (d *Decoder) Decode() (interface{}, error) {
-	header := new(Header)
-	if err := d.readHeader(header); err != nil {
+func (d *Decoder) Decode() (Pdu, error) {
+	if err := d.begin(new(Header), 0); err != nil {
+		return nil, err
+	}
+	pdu := newPdu(d.header.CommandID)
+	if pdu == nil {
+		if entry, ok := d.registry.lookup(d.header.CommandID); ok {
+			pdu = entry.factory()
+		} else {
+			pdu = &RawPdu{}
+		}
+	}
+	if err := d.end(pdu); err != nil {
 		return nil, err
 	}
-	switch header.CommandID {
+	return pdu, nil
+}
+
+// DecodeInto decodes smpp pdu into preallocated pdu of the same command id,
+// header, body and slices of pdu are reused, pdu is left partially filled on error
+func (d *Decoder) DecodeInto(pdu Pdu) error {
+	header := pdu.GetHeader()
+	if header == nil {
+		header = new(Header)
+	}
+	commandID := pdu.CommandID()
+	if _, raw := pdu.(*RawPdu); raw {
+		commandID = 0
+	}
+	if err := d.begin(header, commandID); err != nil {
+		return err
+	}
+	return d.end(pdu)
+}
+
+// begin reads header of next pdu and bounds further reads to its command_length,
+// pdu of other than non zero commandID is left unread
+func (d *Decoder) begin(header *Header, commandID uint32) error {
+	if d.stream != nil && d.src.Len() == 0 {
+		if err := d.stream.Read(d.src); err != nil {
+			return err
+		}
+	}
+	if n := d.src.Len(); n == 0 {
+		// no more pdus, not a malformed one
+		return io.EOF
+	} else if n < int(PduHeaderLength) {
+		return io.ErrUnexpectedEOF
+	}
+	if b := d.src.Bytes(); commandID != 0 && len(b) >= int(PduHeaderLength) && binary.BigEndian.Uint32(b[4:]) != commandID {
+		return statusError(&Header{
+			CommandLength:  binary.BigEndian.Uint32(b),
+			CommandID:      binary.BigEndian.Uint32(b[4:]),
+			CommandStatus:  binary.BigEndian.Uint32(b[8:]),
+			SequenceNumber: binary.BigEndian.Uint32(b[12:]),
+		}, ErrUnsupportedPdu)
+	}
+	// header is peeked so partially buffered pdu is left intact for retry
+	d.body = *bytes.NewBuffer(d.src.Bytes())
+	d.r = &d.body
+	d.start = d.r.Len()
+	d.header = header
+	if err := d.readHeader(d.header); err != nil {
+		// malformed header is dropped as stream is out of sync anyway
+		d.src.Next(int(PduHeaderLength))
+		return statusError(d.header, err)
+	}
+	if uint32(d.src.Len()) < d.header.CommandLength {
+		return statusError(d.header, io.ErrUnexpectedEOF)
+	}
+	d.src.Next(int(PduHeaderLength))
+	d.body = *bytes.NewBuffer(d.src.Next(int(d.header.CommandLength - PduHeaderLength)))
+	d.r = &d.body
+	d.start = int(d.header.CommandLength)
+	return nil
+}
+
+// end reads body of current pdu, errors are returned as StatusError
+func (d *Decoder) end(pdu Pdu) error {
+	if err := d.readPdu(pdu); err != nil {
+		return statusError(d.header, err)
+	}
+	if d.r.Len() > 0 {
+		if d.mode != DecodeLenient {
+			return statusError(d.header, d.fieldError("body", ErrEsmeRinvCmdLen))
+		}
+		d.warn("body", d.consumed(), ErrUnexpectedOctets)
+		d.skip()
+	}
+	if !d.profile.Allows(d.header.CommandID) {
+		return statusError(d.header, ErrUnsupportedVersion)
+	}
+	return nil
+}
+
+// newPdu constructs empty pdu by command id
+func newPdu(commandID uint32) Pdu {
+	switch commandID {
 	case BindReceiver:
-		p := &BindReceiverPdu{Header: header, Body: &BindBody{}}
+		return &BindReceiverPdu{}
+	case BindReceiverResp:
+		return &BindReceiverRespPdu{}
+	case BindTransmitter:
+		return &BindTransmitterPdu{}
+	case BindTransmitterResp:
+		return &BindTransmitterRespPdu{}
+	case BindTransceiver:
+		return &BindTransceiverPdu{}
+	case BindTransceiverResp:
+		return &BindTransceiverRespPdu{}
+	case SubmitSm:
+		return &SubmitSmPdu{}
+	case SubmitSmResp:
+		return &SubmitSmRespPdu{}
+	case DeliverSm:
+		return &DeliverSmPdu{}
+	case DeliverSmResp:
+		return &DeliverSmRespPdu{}
+	case QuerySm:
+		return &QuerySmPdu{}
+	case QuerySmResp:
+		return &QuerySmRespPdu{}
+	case ReplaceSm:
+		return &ReplaceSmPdu{}
+	case ReplaceSmResp:
+		return &ReplaceSmRespPdu{}
+	case CancelSm:
+		return &CancelSmPdu{}
+	case CancelSmResp:
+		return &CancelSmRespPdu{}
+	case SubmitMulti:
+		return &SubmitMultiPdu{}
+	case SubmitMultiResp:
+		return &SubmitMultiRespPdu{}
+	case DataSm:
+		return &DataSmPdu{}
+	case DataSmResp:
+		return &DataSmRespPdu{}
+	case AlertNotification:
+		return &AlertNotificationPdu{}
+	case BroadcastSm:
+		return &BroadcastSmPdu{}
+	case BroadcastSmResp:
+		return &BroadcastSmRespPdu{}
+	case QueryBroadcastSm:
+		return &QueryBroadcastSmPdu{}
+	case QueryBroadcastSmResp:
+		return &QueryBroadcastSmRespPdu{}
+	case CancelBroadcastSm:
+		return &CancelBroadcastSmPdu{}
+	case CancelBroadcastSmResp:
+		return &CancelBroadcastSmRespPdu{}
+	case EnquireLink:
+		return &EnquireLinkPdu{}
+	case EnquireLinkResp:
+		return &EnquireLinkRespPdu{}
+	case GenericNack:
+		return &GenericNackPdu{}
+	case Unbind:
+		return &UnbindPdu{}
+	case UnbindResp:
+		return &UnbindRespPdu{}
+	case OutBind:
+		return &OutBindPdu{}
+	}
+	return nil
+}
+
+// readBindRespTlvs reads bind response tlvs whatever profile is selected and
+// switches to version answered by smsc in successful response, smsc omitting
+// sc_interface_version is assumed to support no tlvs unless the response
+// itself does not exist in v3.3
+func (d *Decoder) readBindRespTlvs(list *TlvList) error {
+	profile := d.profile
+	d.profile = Profile50
+	err := d.readTlvList(list)
+	d.profile = profile
+	if err != nil || d.header.CommandStatus != EsmeRok {
+		return err
+	}
+	if version, err := list.Int(ScInterfaceVersionTlv); err == nil {
+		d.SetVersion(version)
+	} else if Profile33.Allows(d.header.CommandID) {
+		d.SetVersion(InterfaceVersion33)
+	}
+	return nil
+}
+
+// readPdu reads current pdu body into pdu
+func (d *Decoder) readPdu(pdu Pdu) error {
+	switch p := pdu.(type) {
+	case *BindReceiverPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &BindBody{}
+		}
 		if err := d.readBindBody(p.Body); err != nil {
-			return nil, err
+			return err
 		}
-		return p, nil
-	case BindReceiverResp:
-		p := &BindReceiverRespPdu{
-			Header: header,
-			Body:   &BindRespBody{},
-			Tlv:    TlvMap{},
+		d.SetVersion(p.Body.InterfaceVersion)
+	case *BindReceiverRespPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &BindRespBody{}
 		}
+		p.Tlv = p.Tlv[:0]
 		if err := d.readBindRespBody(p.Body); err != nil {
-			return nil, err
+			return err
 		}
-		if d.r.Len() > 0 {
-			if err := d.readTlvMap(p.Tlv); err != nil {
-				return nil, err
-			}
+		return d.readBindRespTlvs(&p.Tlv)
+	case *BindTransmitterPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &BindBody{}
 		}
-		return p, nil
-	case BindTransmitter:
-		p := &BindTransmitterPdu{Header: header, Body: &BindBody{}}
 		if err := d.readBindBody(p.Body); err != nil {
-			return nil, err
+			return err
 		}
-		return p, nil
-	case BindTransmitterResp:
-		p := &BindTransceiverRespPdu{
-			Header: header,
-			Body:   &BindRespBody{},
-			Tlv:    TlvMap{},
+		d.SetVersion(p.Body.InterfaceVersion)
+	case *BindTransmitterRespPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &BindRespBody{}
 		}
+		p.Tlv = p.Tlv[:0]
 		if err := d.readBindRespBody(p.Body); err != nil {
-			return nil, err
+			return err
 		}
-		if d.r.Len() > 0 {
-			if err := d.readTlvMap(p.Tlv); err != nil {
-				return nil, err
-			}
+		return d.readBindRespTlvs(&p.Tlv)
+	case *BindTransceiverPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &BindBody{}
 		}
-		return p, nil
-	case BindTransceiver:
-		p := BindTransceiverPdu{Header: header, Body: &BindBody{}}
 		if err := d.readBindBody(p.Body); err != nil {
-			return nil, err
+			return err
 		}
-		return p, nil
-	case BindTransceiverResp:
-		p := BindTransceiverRespPdu{
-			Header: header,
-			Body:   &BindRespBody{},
-			Tlv:    TlvMap{},
+		d.SetVersion(p.Body.InterfaceVersion)
+	case *BindTransceiverRespPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &BindRespBody{}
 		}
+		p.Tlv = p.Tlv[:0]
 		if err := d.readBindRespBody(p.Body); err != nil {
-			return nil, err
+			return err
 		}
-		if d.r.Len() > 0 {
-			if err := d.readTlvMap(p.Tlv); err != nil {
-				return nil, err
-			}
+		return d.readBindRespTlvs(&p.Tlv)
+	case *SubmitSmPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &SmBody{}
 		}
-		return p, nil
-	case Unbind:
-		return &UnbindPdu{Header: header}, nil
-	case UnbindResp:
-		return &UnbindRespPdu{Header: header}, nil
-	case OutBind:
-		p := &OutBindPdu{Header: header}
-		if err := d.readOutBindBody(p.Body); err != nil {
-			return nil, err
+		p.Tlv = p.Tlv[:0]
+		if err := d.readSmBody(p.Body); err != nil {
+			return err
 		}
-		return p, nil
-	case SubmitSm:
-		p := &SubmitSmPdu{
-			Header: header,
-			Body:   &SmBody{},
-			Tlv:    TlvMap{},
+		return d.readTlvList(&p.Tlv)
+	case *SubmitSmRespPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &SmRespBody{}
+		}
+		return d.readSmRespBody(p.Body)
+	case *DeliverSmPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &SmBody{}
 		}
+		p.Tlv = p.Tlv[:0]
 		if err := d.readSmBody(p.Body); err != nil {
-			return nil, err
+			return err
 		}
-		if d.r.Len() > 0 {
-			if err := d.readTlvMap(p.Tlv); err != nil {
-				return nil, err
-			}
+		return d.readTlvList(&p.Tlv)
+	case *DeliverSmRespPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &SmRespBody{}
 		}
-		return p, nil
-	case SubmitSmResp:
-		p := &SubmitSmRespPdu{Header: header, Body: &SmRespBody{}}
+		return d.readSmRespBody(p.Body)
+	case *QuerySmPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &QuerySmBody{}
+		}
+		return d.readQuerySmBody(p.Body)
+	case *QuerySmRespPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &QuerySmRespBody{}
+		}
+		return d.readQuerySmRespBody(p.Body)
+	case *ReplaceSmPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &ReplaceSmBody{}
+		}
+		return d.readReplaceSmBody(p.Body)
+	case *ReplaceSmRespPdu:
+		p.Header = d.header
+	case *CancelSmPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &CancelSmBody{}
+		}
+		return d.readCancelSmBody(p.Body)
+	case *CancelSmRespPdu:
+		p.Header = d.header
+	case *SubmitMultiPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &SubmitMultiBody{}
+		}
+		p.Tlv = p.Tlv[:0]
+		if err := d.readSubmitMultiBody(p.Body); err != nil {
+			return err
+		}
+		return d.readTlvList(&p.Tlv)
+	case *SubmitMultiRespPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &SubmitMultiRespBody{}
+		}
+		return d.readSubmitMultiRespBody(p.Body)
+	case *DataSmPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &DataSmBody{}
+		}
+		p.Tlv = p.Tlv[:0]
+		if err := d.readDataSmBody(p.Body); err != nil {
+			return err
+		}
+		return d.readTlvList(&p.Tlv)
+	case *DataSmRespPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &SmRespBody{}
+		}
+		p.Tlv = p.Tlv[:0]
 		if err := d.readSmRespBody(p.Body); err != nil {
-			return nil, err
+			return err
 		}
-		return p, nil
-	case DeliverSm:
-		p := &DeliverSmPdu{
-			Header: header,
-			Body:   &SmBody{},
-			Tlv:    TlvMap{},
+		return d.readTlvList(&p.Tlv)
+	case *AlertNotificationPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &AlertNotificationBody{}
 		}
-		if err := d.readSmBody(p.Body); err != nil {
-			return nil, err
+		p.Tlv = p.Tlv[:0]
+		if err := d.readAlertNotificationBody(p.Body); err != nil {
+			return err
 		}
-		if d.r.Len() > 0 {
-			if err := d.readTlvMap(p.Tlv); err != nil {
-				return nil, err
-			}
+		return d.readTlvList(&p.Tlv)
+	case *BroadcastSmPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &BroadcastSmBody{}
 		}
-		return p, nil
-	case DeliverSmResp:
-		p := &DeliverSmRespPdu{Header: header, Body: &SmRespBody{}}
+		p.Tlv = p.Tlv[:0]
+		if err := d.readBroadcastSmBody(p.Body); err != nil {
+			return err
+		}
+		if err := d.readTlvList(&p.Tlv); err != nil {
+			return err
+		}
+		return p.Tlv.require(broadcastSmTlvs...)
+	case *BroadcastSmRespPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &SmRespBody{}
+		}
+		p.Tlv = p.Tlv[:0]
 		if err := d.readSmRespBody(p.Body); err != nil {
-			return nil, err
+			return err
 		}
-		return p, nil
-	case EnquireLink:
-		return &EnquireLinkPdu{Header: header}, nil
-	case EnquireLinkResp:
-		return &EnquireLinkRespPdu{Header: header}, nil
-	case GenericNack:
-		return &GenericNackPdu{Header: header}, nil
+		return d.readTlvList(&p.Tlv)
+	case *QueryBroadcastSmPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &QuerySmBody{}
+		}
+		p.Tlv = p.Tlv[:0]
+		if err := d.readQuerySmBody(p.Body); err != nil {
+			return err
+		}
+		return d.readTlvList(&p.Tlv)
+	case *QueryBroadcastSmRespPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &SmRespBody{}
+		}
+		p.Tlv = p.Tlv[:0]
+		if err := d.readSmRespBody(p.Body); err != nil {
+			return err
+		}
+		return d.readTlvList(&p.Tlv)
+	case *CancelBroadcastSmPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &CancelBroadcastSmBody{}
+		}
+		p.Tlv = p.Tlv[:0]
+		if err := d.readCancelBroadcastSmBody(p.Body); err != nil {
+			return err
+		}
+		return d.readTlvList(&p.Tlv)
+	case *CancelBroadcastSmRespPdu:
+		p.Header = d.header
+	case *EnquireLinkPdu:
+		p.Header = d.header
+	case *EnquireLinkRespPdu:
+		p.Header = d.header
+	case *GenericNackPdu:
+		p.Header = d.header
+	case *UnbindPdu:
+		p.Header = d.header
+	case *UnbindRespPdu:
+		p.Header = d.header
+	case *OutBindPdu:
+		p.Header = d.header
+		if p.Body == nil {
+			p.Body = &OutBindBody{}
+		}
+		return d.readOutBindBody(p.Body)
+	case *RawPdu:
+		p.Header = d.header
+		return d.readRest(&p.Body)
+	default:
+		entry, ok := d.registry.lookup(d.header.CommandID)
+		if !ok {
+			return ErrUnsupportedPdu
+		}
+		var body []byte
+		if err := d.readRest(&body); err != nil {
+			return err
+		}
+		return entry.codec.DecodeBody(pdu, d.header, body)
 	}
-	return nil, ErrUnsupportedPdu
+	return nil
 }
@@ -2,6 +2,9 @@ package smpp
 
 import (
 	"bytes"
+	"errors"
+	"io"
+	"net"
 	"testing"
 )
 
@@ -45,7 +48,7 @@ func TestDecoder_Decode(t *testing.T) {
 		Body: &BindRespBody{
 			SystemID: "test",
 		},
-		Tlv: TlvMap{},
+		Tlv: TlvList{},
 	}
 	buffer1 := new(bytes.Buffer)
 	if err := NewEncoder(buffer1).Encode(req1); err != nil {
@@ -62,3 +65,337 @@ func TestDecoder_Decode(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestDecoder_DecodeBinary(t *testing.T) {
+	req := &SubmitSmPdu{
+		Header: &Header{
+			CommandID:      SubmitSm,
+			CommandStatus:  EsmeRok,
+			SequenceNumber: 3,
+		},
+		Body: &SmBody{
+			SourceAddr:      "sender",
+			DestinationAddr: "79001234567",
+			DataCoding:      DataCodingUcs2,
+			SmLength:        4,
+			ShortMessage:    []byte{0x04, 0x00, 0x00, 0x41},
+		},
+		Tlv: TlvList{
+			{Tag: SarMsgRefNumTlv, Value: []byte{0x00, 0x00}},
+		},
+	}
+	buffer := new(bytes.Buffer)
+	if err := NewEncoder(buffer).Encode(req); err != nil {
+		t.Fatal(err)
+	}
+	rep, err := NewDecoder(buffer).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdu, ok := rep.(*SubmitSmPdu)
+	if !ok {
+		t.Fatal()
+	}
+	if !bytes.Equal(pdu.Body.ShortMessage, req.Body.ShortMessage) {
+		t.Fatalf("short message % x, want % x", pdu.Body.ShortMessage, req.Body.ShortMessage)
+	}
+	tlv, ok := pdu.Tlv.Get(SarMsgRefNumTlv)
+	if !ok {
+		t.Fatal("sar_msg_ref_num missing")
+	}
+	if tlv.Length != 2 || !bytes.Equal(tlv.Value, []byte{0x00, 0x00}) {
+		t.Fatalf("sar_msg_ref_num % x", tlv.Value)
+	}
+}
+
+func TestDecoder_DecodeSubmitMultiResp(t *testing.T) {
+	req := &SubmitMultiRespPdu{
+		Header: &Header{
+			CommandID:      SubmitMultiResp,
+			CommandStatus:  EsmeRok,
+			SequenceNumber: 4,
+		},
+		Body: &SubmitMultiRespBody{
+			MessageID: "abc123",
+			UnsuccessSmes: []UnsuccessSme{
+				{DestAddrTon: TonInternational, DestAddrNpi: NpiE164, DestinationAddr: "79001234567", ErrorStatusCode: EsmeRinvDstAdr},
+				{DestAddrTon: TonNational, DestAddrNpi: NpiE164, DestinationAddr: "9001234567", ErrorStatusCode: EsmeRthrottled},
+			},
+		},
+	}
+	buffer := new(bytes.Buffer)
+	if err := NewEncoder(buffer).Encode(req); err != nil {
+		t.Fatal(err)
+	}
+	rep, err := NewDecoder(buffer).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdu, ok := rep.(*SubmitMultiRespPdu)
+	if !ok {
+		t.Fatal()
+	}
+	if pdu.Body.NoUnsuccess != 2 || len(pdu.Body.UnsuccessSmes) != 2 {
+		t.Fatalf("no_unsuccess %d", pdu.Body.NoUnsuccess)
+	}
+	if err := pdu.Body.UnsuccessSmes[0].Err(); err != ErrEsmeRinvDstAdr {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := pdu.Body.UnsuccessSmes[1].Err(); err != ErrEsmeRthrottled {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestDecoder_DecodeStream(t *testing.T) {
+	client, server := net.Pipe()
+	go func() {
+		w := NewWriterSize(client, 64)
+		for _, f := range encoderFixtures {
+			if err := w.Write(bytes.NewBuffer(f.wire)); err != nil {
+				break
+			}
+		}
+		w.Flush()
+		client.Close()
+	}()
+	decoder := NewStreamDecoder(NewReaderSize(server, 64))
+	for _, f := range encoderFixtures {
+		// bind fixtures negotiate v3.4, broadcast fixtures need v5.0
+		decoder.SetVersion(InterfaceVersion50)
+		pdu, err := decoder.Decode()
+		if err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		buffer := new(bytes.Buffer)
+		if err := NewEncoder(buffer).Encode(pdu); err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		if !bytes.Equal(buffer.Bytes(), f.wire) {
+			t.Fatalf("%s: decoded % x, want % x", f.name, buffer.Bytes(), f.wire)
+		}
+	}
+	if _, err := decoder.Decode(); err != io.EOF {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestDecoder_DecodeStreamTruncated(t *testing.T) {
+	wire := header(16, EnquireLink, 0, 1)
+	decoder := NewStreamDecoder(NewReader(bytes.NewReader(wire[:10])))
+	if _, err := decoder.Decode(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestDecoder_DecodePipelined(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	for _, f := range encoderFixtures {
+		buffer.Write(f.wire)
+	}
+	decoder := NewDecoder(buffer)
+	for _, f := range encoderFixtures {
+		decoder.SetVersion(InterfaceVersion50)
+		pdu, err := decoder.Decode()
+		if err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		if pdu.GetHeader().CommandLength != uint32(len(f.wire)) {
+			t.Fatalf("%s: command length %d, want %d", f.name, pdu.GetHeader().CommandLength, len(f.wire))
+		}
+	}
+	if buffer.Len() != 0 {
+		t.Fatalf("%d bytes left", buffer.Len())
+	}
+}
+
+func TestDecoder_DecodeChunked(t *testing.T) {
+	for _, f := range encoderFixtures {
+		buffer := new(bytes.Buffer)
+		decoder := NewDecoder(buffer)
+		decoder.SetVersion(InterfaceVersion50)
+		for _, split := range []int{len(f.wire) / 2, 10} {
+			buffer.Reset()
+			buffer.Write(f.wire[:split])
+			if _, err := decoder.Decode(); !errors.Is(err, io.ErrUnexpectedEOF) {
+				t.Fatalf("%s: unexpected error %v", f.name, err)
+			}
+			if buffer.Len() != split {
+				t.Fatalf("%s: %d bytes consumed from partial pdu", f.name, split-buffer.Len())
+			}
+			buffer.Write(f.wire[split:])
+			decoder.SetVersion(InterfaceVersion50)
+			pdu, err := decoder.Decode()
+			if err != nil {
+				t.Fatalf("%s: %v", f.name, err)
+			}
+			if pdu.GetHeader().CommandLength != uint32(len(f.wire)) || buffer.Len() != 0 {
+				t.Fatalf("%s: command length %d, %d bytes left", f.name, pdu.GetHeader().CommandLength, buffer.Len())
+			}
+		}
+	}
+}
+
+func TestDecoder_DecodeTrailingGarbage(t *testing.T) {
+	buffer := bytes.NewBuffer(join(
+		header(18, EnquireLink, 0, 1), []byte{0xDE, 0xAD},
+		header(16, EnquireLinkResp, 0, 1),
+	))
+	decoder := NewDecoder(buffer)
+	if _, err := decoder.Decode(); !errors.Is(err, ErrEsmeRinvCmdLen) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	pdu, err := decoder.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := pdu.(*EnquireLinkRespPdu); !ok {
+		t.Fatalf("unexpected pdu %T", pdu)
+	}
+	_, err = NewDecoder(bytes.NewBuffer(header(20, EnquireLink, 0, 3))).Decode()
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || !errors.Is(err, io.ErrUnexpectedEOF) || statusErr.CommandID != EnquireLink || statusErr.SequenceNumber != 3 {
+		t.Fatalf("unexpected error %v", err)
+	}
+	_, err = NewDecoder(bytes.NewBuffer(join(header(20, DataSmResp, 0, 4), cstr("id"), []byte{0x02}))).Decode()
+	if !errors.As(err, &statusErr) || statusErr.Code != EsmeRinvCmdLen || statusErr.Field != "tlv" || statusErr.SequenceNumber != 4 {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestDecoder_DecodeEmpty(t *testing.T) {
+	decoder := NewDecoder(bytes.NewBuffer(header(16, EnquireLink, 0, 1)))
+	if _, err := decoder.Decode(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decoder.Decode(); err != io.EOF {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := decoder.DecodeInto(&EnquireLinkPdu{}); err != io.EOF {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestDecoder_DecodeIntoMismatch(t *testing.T) {
+	wire := header(16, EnquireLink, 0, 9)
+	buffer := bytes.NewBuffer(append([]byte(nil), wire...))
+	decoder := NewDecoder(buffer)
+	into := &SubmitSmPdu{Header: &Header{CommandID: SubmitSm, SequenceNumber: 1}}
+	err := decoder.DecodeInto(into)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || !errors.Is(err, ErrUnsupportedPdu) || statusErr.CommandID != EnquireLink || statusErr.SequenceNumber != 9 {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if into.Header.CommandID != SubmitSm || into.Header.SequenceNumber != 1 || into.Body != nil {
+		t.Fatalf("target modified %+v", into.Header)
+	}
+	if !bytes.Equal(buffer.Bytes(), wire) {
+		t.Fatalf("buffer consumed, % x left", buffer.Bytes())
+	}
+	pdu, err := decoder.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := pdu.(*EnquireLinkPdu); !ok {
+		t.Fatalf("unexpected pdu %T", pdu)
+	}
+}
+
+func TestDecoder_DecodeMode(t *testing.T) {
+	longID := "averyveryverylongid"
+	bind := join(
+		header(uint32(16+len(longID)+1+13), BindTransmitter, 0, 1),
+		cstr(longID), cstr("pass"), cstr("VMA"), []byte{0x34, 0x01, 0x01}, cstr(""),
+	)
+	_, err := NewDecoder(bytes.NewBuffer(bind)).Decode()
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "system_id" || fieldErr.Offset != 16 {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !errors.Is(err, ErrEsmeRinvSysId) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		wire  []byte
+		field string
+		err   error
+	}{
+		{name: "over length", wire: bind, field: "system_id", err: ErrFieldTooLong},
+		{
+			name:  "unterminated",
+			wire:  join(header(22, SubmitSmResp, 0, 2), []byte("abc123")),
+			field: "message_id",
+			err:   ErrFieldUnterminated,
+		},
+		{
+			name:  "unexpected tlv",
+			wire:  join(header(21, EnquireLink, 0, 3), []byte{0x00, 0x1E, 0x00, 0x01, 0x00}),
+			field: "body",
+			err:   ErrUnexpectedOctets,
+		},
+		{
+			name:  "tlv length",
+			wire:  join(header(38, SubmitSm, 0, 4), make([]byte, 17), []byte{0x02, 0x0C, 0x00, 0x01, 0x01}),
+			field: "sar_msg_ref_num",
+			err:   ErrEsmeRinvParLen,
+		},
+	}
+	for _, test := range tests {
+		if _, err := NewDecoder(bytes.NewBuffer(test.wire)).Decode(); err == nil {
+			t.Fatalf("%s: strict decode succeeded", test.name)
+		}
+		decoder := NewDecoder(bytes.NewBuffer(test.wire))
+		decoder.SetMode(DecodeLenient)
+		pdu, err := decoder.Decode()
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		warnings := pdu.GetHeader().Warnings
+		if len(warnings) != 1 || warnings[0].Field != test.field || warnings[0].Err != test.err {
+			t.Fatalf("%s: unexpected warnings %v", test.name, warnings)
+		}
+	}
+}
+
+func TestDecoder_SmLength(t *testing.T) {
+	body := join(
+		cstr(""), []byte{0x00, 0x00}, cstr(""), []byte{0x00, 0x00}, cstr(""),
+		[]byte{0x00, 0x00, 0x00}, cstr(""), cstr(""), []byte{0x00, 0x00, 0x00, 0x00},
+	)
+	tests := []struct {
+		name string
+		wire []byte
+	}{
+		// sm_length left zero so message octets would be read as tlvs
+		{name: "zero", wire: join(header(38, SubmitSm, 0, 1), body, []byte{0x00}, []byte("hello"))},
+		{name: "over", wire: join(header(38, SubmitSm, 0, 2), body, []byte{0x06}, []byte("hello"))},
+		{name: "replace", wire: join(
+			header(30, ReplaceSm, 0, 3), cstr(""), []byte{0x00, 0x00}, cstr(""), cstr(""), cstr(""),
+			[]byte{0x00, 0x00, 0x03}, []byte("hello"),
+		)},
+	}
+	for _, test := range tests {
+		_, err := NewDecoder(bytes.NewBuffer(test.wire)).Decode()
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Field != "sm_length" || !errors.Is(err, ErrEsmeRinvMsgLen) {
+			t.Fatalf("%s: unexpected error %v", test.name, err)
+		}
+		decoder := NewDecoder(bytes.NewBuffer(test.wire))
+		decoder.SetMode(DecodeLenient)
+		pdu, err := decoder.Decode()
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		var message []byte
+		switch p := pdu.(type) {
+		case *SubmitSmPdu:
+			message = p.Body.ShortMessage
+		case *ReplaceSmPdu:
+			message = p.Body.ShortMessage
+		}
+		if string(message) != "hello" || len(pdu.GetHeader().Warnings) != 1 || pdu.GetHeader().Warnings[0].Field != "sm_length" {
+			t.Fatalf("%s: short message %q warnings %v", test.name, message, pdu.GetHeader().Warnings)
+		}
+	}
+}
@@ -0,0 +1,615 @@
+package smpp
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// dumpField is pdu field with number of decoder reads spanning it and readable value
+type dumpField struct {
+	name  string
+	reads int
+	text  string
+}
+
+// SMPP v3.4 - 5.2.5 page 117
+var tonNames = map[uint32]string{
+	TonUnknown:          "unknown",
+	TonInternational:    "international",
+	TonNational:         "national",
+	TonNetworkSpecific:  "network_specific",
+	TonSubscriberNumber: "subscriber_number",
+	TonAlphanumeric:     "alphanumeric",
+	TonAbbreviated:      "abbreviated",
+}
+
+// SMPP v3.4 - 5.2.6 page 118
+var npiNames = map[uint32]string{
+	NpiUnknown:   "unknown",
+	NpiE164:      "isdn_e164",
+	NpiData:      "data_x121",
+	NpiTelex:     "telex_f69",
+	NpiE212:      "land_mobile_e212",
+	NpiNational:  "national",
+	NpiPrivate:   "private",
+	NpiErmes:     "ermes",
+	NpiInternet:  "internet",
+	NpiWapclient: "wap_client_id",
+}
+
+// SMPP v3.4 - 5.2.19 page 126
+var dataCodingNames = map[uint32]string{
+	DataCodingDefault:     "smsc_default",
+	DataCodingIa5:         "ia5_ascii",
+	DataCodingBinaryAlias: "octet_unspecified",
+	DataCodingIso88591:    "latin1",
+	DataCodingBinary:      "octet_unspecified",
+	DataCodingJis:         "jis",
+	DataCodingIso88595:    "cyrillic",
+	DataCodingIso88598:    "latin_hebrew",
+	DataCodingUcs2:        "ucs2",
+	DataCodingPictogram:   "pictogram",
+	DataCodingIso2022Jp:   "iso_2022_jp",
+	DataCodingKanji:       "extended_kanji",
+	DataCodingKsc5601:     "ks_c_5601",
+	DataCodingUtf16be:     "utf16be",
+}
+
+// SMPP v3.4 - 5.2.28 page 130
+var messageStateNames = map[uint32]string{
+	StateEnroute:       "enroute",
+	StateDelivered:     "delivered",
+	StateExpired:       "expired",
+	StateDeleted:       "deleted",
+	StateUndeliverable: "undeliverable",
+	StateAccepted:      "accepted",
+	StateUnknown:       "unknown",
+	StateRejected:      "rejected",
+}
+
+// Dump renders pdu with one field per line as protocol analyzers do
+func Dump(pdu Pdu) string {
+	w := &strings.Builder{}
+	w.WriteString(headerText(pdu))
+	for _, f := range bodyFields(pdu) {
+		fmt.Fprintf(w, "\n  %s: %s", f.name, f.text)
+	}
+	for _, f := range tlvFields(pdu) {
+		fmt.Fprintf(w, "\n  %s: %s", f.name, f.text)
+	}
+	if header := pdu.GetHeader(); header != nil {
+		for _, warning := range header.Warnings {
+			fmt.Fprintf(w, "\n  warning: %v", warning)
+		}
+	}
+	return w.String()
+}
+
+// HexDump renders first pdu of wire as hex lined up with its fields,
+// pdu is decoded leniently so malformed pdus can be inspected and
+// field offsets are taken from decoder
+func HexDump(wire []byte) (string, error) {
+	decoder := NewDecoder(bytes.NewBuffer(wire))
+	decoder.SetMode(DecodeLenient)
+	decoder.tracing = true
+	pdu, err := decoder.Decode()
+	if err != nil {
+		return "", err
+	}
+	header := pdu.GetHeader()
+	fields := []dumpField{
+		{name: "command_length", reads: 1, text: strconv.FormatUint(uint64(header.CommandLength), 10)},
+		{name: "command_id", reads: 1, text: fmt.Sprintf("0x%08X (%s)", header.CommandID, CommandName(header.CommandID))},
+		{name: "command_status", reads: 1, text: fmt.Sprintf("0x%08X (%s)", header.CommandStatus, StatusName(header.CommandStatus))},
+		{name: "sequence_number", reads: 1, text: strconv.FormatUint(uint64(header.SequenceNumber), 10)},
+	}
+	wire = wire[:header.CommandLength]
+	switch {
+	case header.CommandLength <= PduHeaderLength:
+	case newPdu(header.CommandID) == nil:
+		// raw and custom bodies are read at once
+		fields = append(fields, dumpField{name: "body", reads: 1, text: octetsText(wire[PduHeaderLength:])})
+	default:
+		fields = append(fields, bodyFields(pdu)...)
+		fields = append(fields, tlvFields(pdu)...)
+	}
+	offset, read := 0, 0
+	w := &strings.Builder{}
+	for _, f := range fields {
+		if read+f.reads > len(decoder.trace) {
+			// field is missing on wire
+			break
+		}
+		read += f.reads
+		end := int(decoder.trace[read-1])
+		hexLines(w, wire[offset:end], offset, f.name+": "+f.text)
+		offset = end
+	}
+	if offset < len(wire) {
+		hexLines(w, wire[offset:], offset, "unparsed")
+	}
+	for _, warning := range header.Warnings {
+		fmt.Fprintf(w, "warning: %v\n", warning)
+	}
+	return w.String(), nil
+}
+
+// hexLines writes b at offset as rows of 8 octets, first row annotated with text
+func hexLines(w *strings.Builder, b []byte, offset int, text string) {
+	for i := 0; i == 0 || i < len(b); i += 8 {
+		end := i + 8
+		if end > len(b) {
+			end = len(b)
+		}
+		fmt.Fprintf(w, "%04x  %-23s", offset+i, fmt.Sprintf("% x", b[i:end]))
+		if i == 0 {
+			w.WriteString("  " + text)
+		}
+		w.WriteByte('\n')
+	}
+}
+
+// pduString renders pdu on a single line
+func pduString(pdu Pdu) string {
+	w := &strings.Builder{}
+	w.WriteString(headerText(pdu))
+	for _, f := range bodyFields(pdu) {
+		fmt.Fprintf(w, " %s=%s", f.name, f.text)
+	}
+	for _, f := range tlvFields(pdu) {
+		fmt.Fprintf(w, " %s=%s", f.name, f.text)
+	}
+	return w.String()
+}
+
+// headerText renders command name, status and sequence number
+func headerText(pdu Pdu) string {
+	header := pdu.GetHeader()
+	if header == nil {
+		return CommandName(pdu.CommandID())
+	}
+	return fmt.Sprintf("%s status=%s sequence_number=%d",
+		CommandName(pdu.CommandID()), StatusName(header.CommandStatus), header.SequenceNumber)
+}
+
+// tlvFields returns tlvs of pdu in wire order
+func tlvFields(pdu Pdu) []dumpField {
+	p, ok := pdu.(TlvPdu)
+	if !ok {
+		return nil
+	}
+	list := *p.Tlvs()
+	fields := make([]dumpField, len(list))
+	for i, tlv := range list {
+		text := tlvText(tlv)
+		if tlv.Tag == MessagePayloadTlv {
+			text = messageText(tlv.Value, pdu)
+		}
+		// tag, length and value are read one by one
+		fields[i] = dumpField{
+			name:  fmt.Sprintf("%s(0x%04X)", TlvName(tlv.Tag), tlv.Tag),
+			reads: 3,
+			text:  text,
+		}
+	}
+	return fields
+}
+
+// tlvText renders tlv value according to its definition
+func tlvText(tlv Tlv) string {
+	list := TlvList{tlv}
+	switch TlvDefs[tlv.Tag].Kind {
+	case TlvInt8, TlvInt16, TlvInt32:
+		if v, err := list.Int(tlv.Tag); err == nil {
+			return strconv.FormatUint(uint64(v), 10)
+		}
+	case TlvCString:
+		if v, err := list.CString(tlv.Tag); err == nil {
+			return strconv.Quote(v)
+		}
+	}
+	return octetsText(tlv.Value)
+}
+
+// bodyFields returns body fields of builtin, raw or struct bodied custom pdu in wire order
+func bodyFields(pdu Pdu) []dumpField {
+	v := reflect.ValueOf(pdu)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	body := v.Elem().FieldByName("Body")
+	if !body.IsValid() {
+		return nil
+	}
+	if raw, ok := body.Interface().([]byte); ok {
+		return []dumpField{{name: "body", reads: 1, text: octetsText(raw)}}
+	}
+	if body.Kind() != reflect.Ptr || body.IsNil() || body.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	fields := structFields(nil, "", body.Elem(), pdu.CommandID() == DeliverSm)
+	for i := range fields {
+		if fields[i].name == "short_message" {
+			fields[i].text = messageText(body.Elem().FieldByName("ShortMessage").Bytes(), pdu)
+		}
+	}
+	return fields
+}
+
+// structFields appends fields of struct v prefixing their names
+func structFields(fields []dumpField, prefix string, v reflect.Value, deliver bool) []dumpField {
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		name := dumpFieldName(t.Field(i))
+		switch x := v.Field(i).Interface().(type) {
+		case string:
+			fields = append(fields, dumpField{name: prefix + name, reads: 1, text: strconv.Quote(x)})
+		case uint32:
+			switch name {
+			case "number_of_dests":
+				x = uint32(v.FieldByName("DestAddresses").Len())
+			case "no_unsuccess":
+				x = uint32(v.FieldByName("UnsuccessSmes").Len())
+			}
+			fields = append(fields, dumpField{name: prefix + name, reads: 1, text: valueText(name, x, deliver)})
+		case []byte:
+			fields = append(fields, dumpField{name: prefix + name, reads: 1, text: octetsText(x)})
+		case []DestAddress:
+			for j := range x {
+				fields = destAddressFields(fields, fmt.Sprintf("%s%s[%d].", prefix, name, j), &x[j])
+			}
+		case []UnsuccessSme:
+			for j := range x {
+				fields = structFields(fields, fmt.Sprintf("%s%s[%d].", prefix, name, j), reflect.ValueOf(x[j]), deliver)
+			}
+		}
+	}
+	return fields
+}
+
+// destAddressFields appends submit_multi destination fields present on wire
+func destAddressFields(fields []dumpField, prefix string, dest *DestAddress) []dumpField {
+	fields = append(fields, dumpField{name: prefix + "dest_flag", reads: 1, text: valueText("dest_flag", dest.DestFlag, false)})
+	switch dest.DestFlag {
+	case DestFlagSme:
+		fields = append(fields,
+			dumpField{name: prefix + "dest_addr_ton", reads: 1, text: valueText("dest_addr_ton", dest.DestAddrTon, false)},
+			dumpField{name: prefix + "dest_addr_npi", reads: 1, text: valueText("dest_addr_npi", dest.DestAddrNpi, false)},
+			dumpField{name: prefix + "destination_addr", reads: 1, text: strconv.Quote(dest.DestinationAddr)},
+		)
+	case DestFlagDistlist:
+		fields = append(fields, dumpField{name: prefix + "dl_name", reads: 1, text: strconv.Quote(dest.DlName)})
+	}
+	return fields
+}
+
+// dumpFieldName returns spec field name from json tag or struct field name
+func dumpFieldName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	w := &strings.Builder{}
+	for i, r := range strings.Replace(field.Name, "ID", "Id", -1) {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				w.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		w.WriteRune(r)
+	}
+	return w.String()
+}
+
+// valueText renders integer field with its symbolic meaning
+func valueText(name string, v uint32, deliver bool) string {
+	switch {
+	case strings.HasSuffix(name, "_ton"):
+		return namedText(v, tonNames)
+	case strings.HasSuffix(name, "_npi"):
+		return namedText(v, npiNames)
+	case name == "data_coding":
+		return namedText(v, dataCodingNames)
+	case name == "message_state":
+		return namedText(v, messageStateNames)
+	case name == "esm_class":
+		return fmt.Sprintf("0x%02X (%s)", v, esmClassText(v, deliver))
+	case name == "registered_delivery":
+		return fmt.Sprintf("0x%02X (%s)", v, registeredDeliveryText(v))
+	case name == "interface_version":
+		return fmt.Sprintf("0x%02X", v)
+	case name == "error_status_code":
+		return fmt.Sprintf("0x%08X (%s)", v, StatusName(v))
+	case name == "dest_flag":
+		return namedText(v, map[uint32]string{DestFlagSme: "sme_address", DestFlagDistlist: "distribution_list"})
+	}
+	return strconv.FormatUint(uint64(v), 10)
+}
+
+// namedText renders integer with its name when known
+func namedText(v uint32, names map[uint32]string) string {
+	if name, ok := names[v]; ok {
+		return fmt.Sprintf("%d (%s)", v, name)
+	}
+	return strconv.FormatUint(uint64(v), 10)
+}
+
+// esmClassText renders esm_class messaging mode, message type and gsm features
+// SMPP v3.4 - 5.2.12 page 121
+func esmClassText(v uint32, deliver bool) string {
+	var s []string
+	if !deliver {
+		s = append(s, [...]string{"default_mode", "datagram_mode", "forward_mode", "store_and_forward_mode"}[v&0x03])
+	}
+	switch t := v & 0x3C; {
+	case t == EsmSubmitDefault:
+		s = append(s, "default_type")
+	case deliver && t == EsmDeliverSmscReceipt:
+		s = append(s, "smsc_delivery_receipt")
+	case t == EsmSubmitTypeEsmeDAck:
+		s = append(s, "delivery_ack")
+	case t == EsmSubmitTypeEsmeUAck:
+		s = append(s, "manual_ack")
+	case deliver && t == EsmDeliverConvAbort:
+		s = append(s, "conversation_abort")
+	case deliver && t == EsmDeliverIdn:
+		s = append(s, "intermediate_notification")
+	default:
+		s = append(s, "reserved_type")
+	}
+	if v&EsmUdhi != 0 {
+		s = append(s, "udhi")
+	}
+	if v&EsmReplyPath != 0 {
+		s = append(s, "reply_path")
+	}
+	return strings.Join(s, ",")
+}
+
+// registeredDeliveryText renders smsc receipt, sme ack and intermediate notification bits
+// SMPP v3.4 - 5.2.17 page 124
+func registeredDeliveryText(v uint32) string {
+	s := []string{[...]string{"no_receipt", "receipt", "receipt_on_failure", "reserved_receipt"}[v&0x03]}
+	switch v & 0x0C {
+	case 0x04:
+		s = append(s, "delivery_ack")
+	case 0x08:
+		s = append(s, "manual_ack")
+	case 0x0C:
+		s = append(s, "delivery_and_manual_ack")
+	}
+	if v&0x10 != 0 {
+		s = append(s, "intermediate_notification")
+	}
+	return strings.Join(s, ",")
+}
+
+// messageText renders short message or message payload as hex followed by
+// text decoded by data_coding of pdu body, user data header is not decoded
+func messageText(b []byte, pdu Pdu) string {
+	var dataCoding, esmClass uint32
+	if body := pduBody(pdu); body.IsValid() && body.Kind() == reflect.Ptr && !body.IsNil() && body.Elem().Kind() == reflect.Struct {
+		if v := body.Elem().FieldByName("DataCoding"); v.IsValid() && v.Kind() == reflect.Uint32 {
+			dataCoding = uint32(v.Uint())
+		}
+		if v := body.Elem().FieldByName("EsmClass"); v.IsValid() && v.Kind() == reflect.Uint32 {
+			esmClass = uint32(v.Uint())
+		}
+	}
+	if dataCoding != DataCodingUcs2 && dataCoding != DataCodingIso88591 {
+		return octetsText(b)
+	}
+	text := b
+	if esmClass&EsmUdhi != 0 {
+		if len(b) == 0 || int(b[0]) >= len(b) {
+			return octetsText(b)
+		}
+		text = b[1+int(b[0]):]
+	}
+	var runes []rune
+	if dataCoding == DataCodingUcs2 {
+		if len(text)%2 != 0 {
+			return octetsText(b)
+		}
+		units := make([]uint16, len(text)/2)
+		for i := range units {
+			units[i] = uint16(text[2*i])<<8 | uint16(text[2*i+1])
+		}
+		runes = utf16.Decode(units)
+	} else {
+		runes = make([]rune, len(text))
+		for i, c := range text {
+			runes[i] = rune(c)
+		}
+	}
+	return fmt.Sprintf("[% x] %s", b, strconv.Quote(string(runes)))
+}
+
+// octetsText renders octets as hex followed by text when printable
+func octetsText(b []byte) string {
+	text := fmt.Sprintf("[% x]", b)
+	for _, c := range b {
+		if c < 0x20 || c > 0x7E {
+			return text
+		}
+	}
+	if len(b) > 0 {
+		text += " " + strconv.Quote(string(b))
+	}
+	return text
+}
+
+// String renders pdu on a single line
+func (p *BindReceiverPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *BindReceiverRespPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *BindTransmitterPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *BindTransmitterRespPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *BindTransceiverPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *BindTransceiverRespPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *SubmitSmPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *SubmitSmRespPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *DeliverSmPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *DeliverSmRespPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *QuerySmPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *QuerySmRespPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *ReplaceSmPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *ReplaceSmRespPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *CancelSmPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *CancelSmRespPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *SubmitMultiPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *SubmitMultiRespPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *DataSmPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *DataSmRespPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *AlertNotificationPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *BroadcastSmPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *BroadcastSmRespPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *QueryBroadcastSmPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *QueryBroadcastSmRespPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *CancelBroadcastSmPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *CancelBroadcastSmRespPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *EnquireLinkPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *EnquireLinkRespPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *GenericNackPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *UnbindPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *UnbindRespPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *OutBindPdu) String() string {
+	return pduString(p)
+}
+
+// String renders pdu on a single line
+func (p *RawPdu) String() string {
+	return pduString(p)
+}
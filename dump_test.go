@@ -0,0 +1,99 @@
+package smpp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	pdu := &SubmitSmPdu{
+		Header: &Header{CommandID: SubmitSm, CommandStatus: EsmeRok, SequenceNumber: 6},
+		Body: &SmBody{
+			SourceAddrTon:      TonAlphanumeric,
+			SourceAddr:         "Alert",
+			DestAddrTon:        TonInternational,
+			DestAddrNpi:        NpiE164,
+			DestinationAddr:    "79001234567",
+			EsmClass:           EsmSubmitModeStoreAndForward,
+			RegisteredDelivery: 0x01,
+			DataCoding:         DataCodingUcs2,
+			ShortMessage:       []byte{0x00, 'h', 0x04, 0x3F},
+		},
+		Tlv: TlvList{},
+	}
+	pdu.Tlv.Set(ReceiptedMessageIdTlv, []byte("abc123\x00"))
+	dump := Dump(pdu)
+	for _, want := range []string{
+		"submit_sm status=ESME_ROK sequence_number=6",
+		"source_addr_ton: 5 (alphanumeric)",
+		"dest_addr_npi: 1 (isdn_e164)",
+		"esm_class: 0x03 (store_and_forward_mode,default_type)",
+		"registered_delivery: 0x01 (receipt)",
+		"data_coding: 8 (ucs2)",
+		"short_message: [00 68 04 3f] \"h\u043f\"",
+		"receipted_message_id(0x001E): \"abc123\"",
+	} {
+		if !strings.Contains(dump, want) {
+			t.Fatalf("dump misses %q:\n%s", want, dump)
+		}
+	}
+	if s := pdu.String(); strings.Contains(s, "\n") || !strings.HasPrefix(s, "submit_sm status=ESME_ROK") {
+		t.Fatalf("unexpected string %q", s)
+	}
+	deliver := &DeliverSmPdu{
+		Header: &Header{CommandID: DeliverSm, SequenceNumber: 8},
+		Body:   &SmBody{EsmClass: EsmUdhi, DataCoding: DataCodingIso88591, ShortMessage: []byte{0x01, 0xAA, 'c', 'a', 'f', 0xE9}},
+	}
+	if dump := Dump(deliver); !strings.Contains(dump, "short_message: [01 aa 63 61 66 e9] \"caf\u00e9\"") {
+		t.Fatalf("latin1 not decoded:\n%s", dump)
+	}
+	resp := &GenericNackPdu{Header: &Header{CommandID: GenericNack, CommandStatus: EsmeRinvCmdId, SequenceNumber: 7}}
+	if s := resp.String(); s != "generic_nack status=ESME_RINVCMDID sequence_number=7" {
+		t.Fatalf("unexpected string %q", s)
+	}
+}
+
+func TestHexDump(t *testing.T) {
+	for _, f := range encoderFixtures {
+		dump, err := HexDump(f.wire)
+		if err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		// each row starts with its offset and hex columns sum up to the wire
+		var wire []byte
+		for _, line := range strings.Split(strings.TrimSuffix(dump, "\n"), "\n") {
+			if strings.HasPrefix(line, "warning") {
+				continue
+			}
+			for _, h := range strings.Fields(line[6:29]) {
+				var b byte
+				for _, c := range h {
+					b = b<<4 | byte(strings.IndexRune("0123456789abcdef", c))
+				}
+				wire = append(wire, b)
+			}
+			if strings.Contains(line, "unparsed") {
+				t.Fatalf("%s: unparsed octets:\n%s", f.name, dump)
+			}
+		}
+		if !bytes.Equal(wire, f.wire) {
+			t.Fatalf("%s: dumped % x, want % x", f.name, wire, f.wire)
+		}
+	}
+	// unterminated message_id spans exactly the octets decoder took
+	dump, err := HexDump(join(header(19, SubmitSmResp, 0, 2), []byte("abc")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(dump, "0010  61 62 63                 message_id: \"abc\"\nwarning:") || strings.Contains(dump, "unparsed") {
+		t.Fatalf("unexpected dump:\n%s", dump)
+	}
+	dump, err = HexDump(join(header(20, EnquireLink, 0, 1), []byte{0xDE, 0xAD, 0xBE, 0xEF}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(dump, "0010  de ad be ef              unparsed") {
+		t.Fatalf("unexpected dump:\n%s", dump)
+	}
+}
@@ -8,25 +8,101 @@ import (
 
 // Encoder encodes smpp pdu
 type Encoder struct {
-	w *bytes.Buffer
-	h *bytes.Buffer
-	b *bytes.Buffer
+	w        *bytes.Buffer
+	h        *bytes.Buffer
+	b        *bytes.Buffer
+	profile  *Profile
+	registry *Registry
+	// payload enables moving long short messages to message_payload
+	payload bool
+	// overflow is short message moved to message_payload of current pdu
+	overflow []byte
 }
 
 // NewEncoder constructs Encoder
 func NewEncoder(w *bytes.Buffer) *Encoder {
 	return &Encoder{
-		w: w,
-		h: &bytes.Buffer{},
-		b: &bytes.Buffer{},
+		w:        w,
+		h:        &bytes.Buffer{},
+		b:        &bytes.Buffer{},
+		profile:  Profile50,
+		registry: DefaultRegistry,
 	}
 }
 
-// writeIntField writes smpp integer
-func (e *Encoder) writeInt(v *uint32, b *bytes.Buffer) error {
-	p := make([]byte, 4)
-	binary.BigEndian.PutUint32(p, *v)
-	n, err := b.Write(p)
+// Reset discards encoder state and switches it to w, so encoders can be pooled
+func (e *Encoder) Reset(w *bytes.Buffer) {
+	e.w = w
+	e.h.Reset()
+	e.b.Reset()
+	e.profile = Profile50
+	e.registry = DefaultRegistry
+	e.payload = false
+	e.overflow = nil
+}
+
+// SetMessagePayload moves short messages longer than MaxShortMessageLength
+// to message_payload tlv instead of failing, pdus without tlvs still fail
+func (e *Encoder) SetMessagePayload(enabled bool) {
+	e.payload = enabled
+}
+
+// SetRegistry selects registry used for custom pdus
+func (e *Encoder) SetRegistry(registry *Registry) {
+	e.registry = registry
+}
+
+// Validate checks pdu against spec as encoder would encode it, short message
+// too long for short_message is accepted when SetMessagePayload moves it
+func (e *Encoder) Validate(pdu Pdu) error {
+	v := &validator{payload: e.payload && e.profile.Tlv}
+	switch p := pdu.(type) {
+	case *SubmitSmPdu:
+		return p.validate(v)
+	case *DeliverSmPdu:
+		return p.validate(v)
+	case *SubmitMultiPdu:
+		return p.validate(v)
+	case Validator:
+		return p.Validate()
+	}
+	return nil
+}
+
+// SetVersion selects interface version profile used for encoding
+func (e *Encoder) SetVersion(version uint32) {
+	e.profile = ProfileFor(version)
+}
+
+// Version returns interface version used for encoding
+func (e *Encoder) Version() uint32 {
+	return e.profile.Version
+}
+
+// writeInt8 writes smpp 1 octet integer
+func (e *Encoder) writeInt8(v *uint32, b *bytes.Buffer) error {
+	return b.WriteByte(byte(*v))
+}
+
+// writeInt16 writes smpp 2 octet integer
+func (e *Encoder) writeInt16(v *uint32, b *bytes.Buffer) error {
+	var p [2]byte
+	binary.BigEndian.PutUint16(p[:], uint16(*v))
+	n, err := b.Write(p[:])
+	if err != nil {
+		return err
+	}
+	if n < len(p) {
+		return io.EOF
+	}
+	return nil
+}
+
+// writeInt32 writes smpp 4 octet integer
+func (e *Encoder) writeInt32(v *uint32, b *bytes.Buffer) error {
+	var p [4]byte
+	binary.BigEndian.PutUint32(p[:], *v)
+	n, err := b.Write(p[:])
 	if err != nil {
 		return err
 	}
@@ -36,7 +112,7 @@ func (e *Encoder) writeInt(v *uint32, b *bytes.Buffer) error {
 	return nil
 }
 
-// writeString writes smpp octet string
+// writeString writes smpp c-octet string
 func (e *Encoder) writeString(v *string, b *bytes.Buffer) error {
 	n, err := b.WriteString(*v)
 	if err != nil {
@@ -48,39 +124,100 @@ func (e *Encoder) writeString(v *string, b *bytes.Buffer) error {
 	return b.WriteByte(byte(0))
 }
 
-// writeString writes smpp pdu header
-func (e *Encoder) writeHeader(header *Header) error {
+// writeOctets writes smpp octet string without terminator
+func (e *Encoder) writeOctets(v []byte, b *bytes.Buffer) error {
+	n, err := b.Write(v)
+	if err != nil {
+		return err
+	}
+	if n < len(v) {
+		return io.EOF
+	}
+	return nil
+}
+
+// writeHeader writes smpp pdu header with command id of pdu type
+func (e *Encoder) writeHeader(header *Header, commandID uint32) error {
+	if !e.profile.Allows(commandID) {
+		return ErrUnsupportedVersion
+	}
 	header.CommandLength = uint32(e.b.Len()) + PduHeaderLength
-	if err := e.writeInt(&header.CommandLength, e.h); err != nil {
+	if err := e.writeInt32(&header.CommandLength, e.h); err != nil {
 		return err
 	}
-	if err := e.writeInt(&header.CommandID, e.h); err != nil {
+	if err := e.writeInt32(&commandID, e.h); err != nil {
 		return err
 	}
-	if err := e.writeInt(&header.CommandStatus, e.h); err != nil {
+	if err := e.writeInt32(&header.CommandStatus, e.h); err != nil {
 		return err
 	}
-	return e.writeInt(&header.SequenceNumber, e.h)
+	return e.writeInt32(&header.SequenceNumber, e.h)
 }
 
-// writeTlvMap writes smpp tlv map
-func (e *Encoder) writeTlvMap(tlvMap *TlvMap) error {
-	for _, tlv := range *tlvMap {
-		if err := e.writeInt(&tlv.Tag, e.b); err != nil {
+// writeTlvList writes smpp tlv list
+func (e *Encoder) writeTlvList(list TlvList) error {
+	if !e.profile.Tlv {
+		// v3.3 peers reject tlvs so they are dropped
+		return nil
+	}
+	for i := range list {
+		tlv := &list[i]
+		if err := tlv.validate(); err != nil {
 			return err
 		}
-		if err := e.writeInt(&tlv.Length, e.b); err != nil {
+		tlv.Length = uint32(len(tlv.Value))
+		if err := e.writeTlv(tlv); err != nil {
 			return err
 		}
-		if err := e.writeString(&tlv.Value, e.b); err != nil {
+	}
+	if e.overflow != nil {
+		if _, ok := list.Get(MessagePayloadTlv); ok {
+			return ErrEsmeRinvOptParamVal
+		}
+		tlv := Tlv{Tag: MessagePayloadTlv, Length: uint32(len(e.overflow)), Value: e.overflow}
+		e.overflow = nil
+		if err := tlv.validate(); err != nil {
 			return err
 		}
+		return e.writeTlv(&tlv)
 	}
 	return nil
 }
 
+// writeTlv writes smpp tlv
+func (e *Encoder) writeTlv(tlv *Tlv) error {
+	if err := e.writeInt16(&tlv.Tag, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt16(&tlv.Length, e.b); err != nil {
+		return err
+	}
+	return e.writeOctets(tlv.Value, e.b)
+}
+
+// writeShortMessage writes sm_length derived from message followed by message,
+// too long message is left for message_payload when pdu carries tlvs
+func (e *Encoder) writeShortMessage(message []byte, tlvs bool) error {
+	if len(message) > MaxShortMessageLength {
+		if !e.payload || !tlvs || !e.profile.Tlv {
+			return ErrEsmeRinvMsgLen
+		}
+		e.overflow = message
+		return e.b.WriteByte(0)
+	}
+	if err := e.b.WriteByte(byte(len(message))); err != nil {
+		return err
+	}
+	return e.writeOctets(message, e.b)
+}
+
 // writeBindBody writes smpp bind body
 func (e *Encoder) writeBindBody(body *BindBody) error {
+	if body == nil {
+		return ErrFieldMissing
+	}
+	// bind selects profile of version it asks for
+	e.SetVersion(body.InterfaceVersion)
 	if err := e.writeString(&body.SystemID, e.b); err != nil {
 		return err
 	}
@@ -90,13 +227,13 @@ func (e *Encoder) writeBindBody(body *BindBody) error {
 	if err := e.writeString(&body.SystemType, e.b); err != nil {
 		return err
 	}
-	if err := e.writeInt(&body.InterfaceVersion, e.b); err != nil {
+	if err := e.writeInt8(&body.InterfaceVersion, e.b); err != nil {
 		return err
 	}
-	if err := e.writeInt(&body.AddrTon, e.b); err != nil {
+	if err := e.writeInt8(&body.AddrTon, e.b); err != nil {
 		return err
 	}
-	if err := e.writeInt(&body.AddrNpi, e.b); err != nil {
+	if err := e.writeInt8(&body.AddrNpi, e.b); err != nil {
 		return err
 	}
 	return e.writeString(&body.AddressRange, e.b)
@@ -104,11 +241,17 @@ func (e *Encoder) writeBindBody(body *BindBody) error {
 
 // writeBindRespBody writes smpp bind resp
 func (e *Encoder) writeBindRespBody(body *BindRespBody) error {
+	if body == nil {
+		return ErrFieldMissing
+	}
 	return e.writeString(&body.SystemID, e.b)
 }
 
 // writeOutBindBody writes smpp outbind body
 func (e *Encoder) writeOutBindBody(body *OutBindBody) error {
+	if body == nil {
+		return ErrFieldMissing
+	}
 	if err := e.writeString(&body.SystemID, e.b); err != nil {
 		return err
 	}
@@ -117,34 +260,37 @@ func (e *Encoder) writeOutBindBody(body *OutBindBody) error {
 
 // writeSmBody writes smpp short message body
 func (e *Encoder) writeSmBody(body *SmBody) error {
+	if body == nil {
+		return ErrFieldMissing
+	}
 	if err := e.writeString(&body.ServiceType, e.b); err != nil {
 		return err
 	}
-	if err := e.writeInt(&body.SourceAddrTon, e.b); err != nil {
+	if err := e.writeInt8(&body.SourceAddrTon, e.b); err != nil {
 		return err
 	}
-	if err := e.writeInt(&body.SourceAddrNpi, e.b); err != nil {
+	if err := e.writeInt8(&body.SourceAddrNpi, e.b); err != nil {
 		return err
 	}
 	if err := e.writeString(&body.SourceAddr, e.b); err != nil {
 		return err
 	}
-	if err := e.writeInt(&body.DestAddrTon, e.b); err != nil {
+	if err := e.writeInt8(&body.DestAddrTon, e.b); err != nil {
 		return err
 	}
-	if err := e.writeInt(&body.DestAddrNpi, e.b); err != nil {
+	if err := e.writeInt8(&body.DestAddrNpi, e.b); err != nil {
 		return err
 	}
 	if err := e.writeString(&body.DestinationAddr, e.b); err != nil {
 		return err
 	}
-	if err := e.writeInt(&body.EsmClass, e.b); err != nil {
+	if err := e.writeInt8(&body.EsmClass, e.b); err != nil {
 		return err
 	}
-	if err := e.writeInt(&body.ProtocolID, e.b); err != nil {
+	if err := e.writeInt8(&body.ProtocolID, e.b); err != nil {
 		return err
 	}
-	if err := e.writeInt(&body.PriorityFlag, e.b); err != nil {
+	if err := e.writeInt8(&body.PriorityFlag, e.b); err != nil {
 		return err
 	}
 	if err := e.writeString(&body.ScheduleDeliveryTime, e.b); err != nil {
@@ -153,35 +299,358 @@ func (e *Encoder) writeSmBody(body *SmBody) error {
 	if err := e.writeString(&body.ValidityPeriod, e.b); err != nil {
 		return err
 	}
-	if err := e.writeInt(&body.RegisteredDelivery, e.b); err != nil {
-		return err
-	}
-	if err := e.writeInt(&body.ReplaceIfPresentFlag, e.b); err != nil {
+	if err := e.writeInt8(&body.RegisteredDelivery, e.b); err != nil {
 		return err
 	}
-	if err := e.writeInt(&body.DataCoding, e.b); err != nil {
+	if err := e.writeInt8(&body.ReplaceIfPresentFlag, e.b); err != nil {
 		return err
 	}
-	if err := e.writeInt(&body.SmDefaultMessageID, e.b); err != nil {
+	if err := e.writeInt8(&body.DataCoding, e.b); err != nil {
 		return err
 	}
-	if err := e.writeInt(&body.SmLength, e.b); err != nil {
+	if err := e.writeInt8(&body.SmDefaultMessageID, e.b); err != nil {
 		return err
 	}
-	return e.writeString(&body.ShortMessage, e.b)
+	return e.writeShortMessage(body.ShortMessage, true)
 }
 
 // writeSmRespBody writes smpp message response
 func (e *Encoder) writeSmRespBody(body *SmRespBody) error {
+	if body == nil {
+		return ErrFieldMissing
+	}
 	return e.writeString(&body.MessageID, e.b)
 }
 
+// writeQuerySmBody writes smpp query sm body
+func (e *Encoder) writeQuerySmBody(body *QuerySmBody) error {
+	if body == nil {
+		return ErrFieldMissing
+	}
+	if err := e.writeString(&body.MessageID, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.SourceAddrTon, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.SourceAddrNpi, e.b); err != nil {
+		return err
+	}
+	return e.writeString(&body.SourceAddr, e.b)
+}
+
+// writeQuerySmRespBody writes smpp query sm resp body
+func (e *Encoder) writeQuerySmRespBody(body *QuerySmRespBody) error {
+	if body == nil {
+		return ErrFieldMissing
+	}
+	if err := e.writeString(&body.MessageID, e.b); err != nil {
+		return err
+	}
+	if err := e.writeString(&body.FinalDate, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.MessageState, e.b); err != nil {
+		return err
+	}
+	return e.writeInt8(&body.ErrorCode, e.b)
+}
+
+// writeReplaceSmBody writes smpp replace sm body
+func (e *Encoder) writeReplaceSmBody(body *ReplaceSmBody) error {
+	if body == nil {
+		return ErrFieldMissing
+	}
+	if err := e.writeString(&body.MessageID, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.SourceAddrTon, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.SourceAddrNpi, e.b); err != nil {
+		return err
+	}
+	if err := e.writeString(&body.SourceAddr, e.b); err != nil {
+		return err
+	}
+	if err := e.writeString(&body.ScheduleDeliveryTime, e.b); err != nil {
+		return err
+	}
+	if err := e.writeString(&body.ValidityPeriod, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.RegisteredDelivery, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.SmDefaultMessageID, e.b); err != nil {
+		return err
+	}
+	return e.writeShortMessage(body.ShortMessage, false)
+}
+
+// writeCancelSmBody writes smpp cancel sm body
+func (e *Encoder) writeCancelSmBody(body *CancelSmBody) error {
+	if body == nil {
+		return ErrFieldMissing
+	}
+	if err := e.writeString(&body.ServiceType, e.b); err != nil {
+		return err
+	}
+	if err := e.writeString(&body.MessageID, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.SourceAddrTon, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.SourceAddrNpi, e.b); err != nil {
+		return err
+	}
+	if err := e.writeString(&body.SourceAddr, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.DestAddrTon, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.DestAddrNpi, e.b); err != nil {
+		return err
+	}
+	return e.writeString(&body.DestinationAddr, e.b)
+}
+
+// writeDestAddress writes smpp submit multi destination address
+func (e *Encoder) writeDestAddress(addr *DestAddress) error {
+	if err := e.writeInt8(&addr.DestFlag, e.b); err != nil {
+		return err
+	}
+	switch addr.DestFlag {
+	case DestFlagSme:
+		if err := e.writeInt8(&addr.DestAddrTon, e.b); err != nil {
+			return err
+		}
+		if err := e.writeInt8(&addr.DestAddrNpi, e.b); err != nil {
+			return err
+		}
+		return e.writeString(&addr.DestinationAddr, e.b)
+	case DestFlagDistlist:
+		return e.writeString(&addr.DlName, e.b)
+	}
+	return ErrEsmeRinvDestFlag
+}
+
+// writeSubmitMultiBody writes smpp submit multi body
+func (e *Encoder) writeSubmitMultiBody(body *SubmitMultiBody) error {
+	if body == nil {
+		return ErrFieldMissing
+	}
+	if len(body.DestAddresses) == 0 || uint32(len(body.DestAddresses)) > MaxNumberOfDests {
+		return ErrEsmeRinvNumDests
+	}
+	body.NumberOfDests = uint32(len(body.DestAddresses))
+	if err := e.writeString(&body.ServiceType, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.SourceAddrTon, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.SourceAddrNpi, e.b); err != nil {
+		return err
+	}
+	if err := e.writeString(&body.SourceAddr, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.NumberOfDests, e.b); err != nil {
+		return err
+	}
+	for i := range body.DestAddresses {
+		if err := e.writeDestAddress(&body.DestAddresses[i]); err != nil {
+			return err
+		}
+	}
+	if err := e.writeInt8(&body.EsmClass, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.ProtocolID, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.PriorityFlag, e.b); err != nil {
+		return err
+	}
+	if err := e.writeString(&body.ScheduleDeliveryTime, e.b); err != nil {
+		return err
+	}
+	if err := e.writeString(&body.ValidityPeriod, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.RegisteredDelivery, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.ReplaceIfPresentFlag, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.DataCoding, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.SmDefaultMessageID, e.b); err != nil {
+		return err
+	}
+	return e.writeShortMessage(body.ShortMessage, true)
+}
+
+// writeSubmitMultiRespBody writes smpp submit multi resp body
+func (e *Encoder) writeSubmitMultiRespBody(body *SubmitMultiRespBody) error {
+	if body == nil {
+		return ErrFieldMissing
+	}
+	if uint32(len(body.UnsuccessSmes)) > MaxNumberOfDests {
+		return ErrEsmeRinvNumDests
+	}
+	body.NoUnsuccess = uint32(len(body.UnsuccessSmes))
+	if err := e.writeString(&body.MessageID, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.NoUnsuccess, e.b); err != nil {
+		return err
+	}
+	for i := range body.UnsuccessSmes {
+		sme := &body.UnsuccessSmes[i]
+		if err := e.writeInt8(&sme.DestAddrTon, e.b); err != nil {
+			return err
+		}
+		if err := e.writeInt8(&sme.DestAddrNpi, e.b); err != nil {
+			return err
+		}
+		if err := e.writeString(&sme.DestinationAddr, e.b); err != nil {
+			return err
+		}
+		if err := e.writeInt32(&sme.ErrorStatusCode, e.b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDataSmBody writes smpp data sm body
+func (e *Encoder) writeDataSmBody(body *DataSmBody) error {
+	if body == nil {
+		return ErrFieldMissing
+	}
+	if err := e.writeString(&body.ServiceType, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.SourceAddrTon, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.SourceAddrNpi, e.b); err != nil {
+		return err
+	}
+	if err := e.writeString(&body.SourceAddr, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.DestAddrTon, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.DestAddrNpi, e.b); err != nil {
+		return err
+	}
+	if err := e.writeString(&body.DestinationAddr, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.EsmClass, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.RegisteredDelivery, e.b); err != nil {
+		return err
+	}
+	return e.writeInt8(&body.DataCoding, e.b)
+}
+
+// writeAlertNotificationBody writes smpp alert notification body
+func (e *Encoder) writeAlertNotificationBody(body *AlertNotificationBody) error {
+	if body == nil {
+		return ErrFieldMissing
+	}
+	if err := e.writeInt8(&body.SourceAddrTon, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.SourceAddrNpi, e.b); err != nil {
+		return err
+	}
+	if err := e.writeString(&body.SourceAddr, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.EsmeAddrTon, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.EsmeAddrNpi, e.b); err != nil {
+		return err
+	}
+	return e.writeString(&body.EsmeAddr, e.b)
+}
+
+// writeBroadcastSmBody writes smpp broadcast sm body
+func (e *Encoder) writeBroadcastSmBody(body *BroadcastSmBody) error {
+	if body == nil {
+		return ErrFieldMissing
+	}
+	if err := e.writeString(&body.ServiceType, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.SourceAddrTon, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.SourceAddrNpi, e.b); err != nil {
+		return err
+	}
+	if err := e.writeString(&body.SourceAddr, e.b); err != nil {
+		return err
+	}
+	if err := e.writeString(&body.MessageID, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.PriorityFlag, e.b); err != nil {
+		return err
+	}
+	if err := e.writeString(&body.ScheduleDeliveryTime, e.b); err != nil {
+		return err
+	}
+	if err := e.writeString(&body.ValidityPeriod, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.ReplaceIfPresentFlag, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.DataCoding, e.b); err != nil {
+		return err
+	}
+	return e.writeInt8(&body.SmDefaultMessageID, e.b)
+}
+
+// writeCancelBroadcastSmBody writes smpp cancel broadcast sm body
+func (e *Encoder) writeCancelBroadcastSmBody(body *CancelBroadcastSmBody) error {
+	if body == nil {
+		return ErrFieldMissing
+	}
+	if err := e.writeString(&body.ServiceType, e.b); err != nil {
+		return err
+	}
+	if err := e.writeString(&body.MessageID, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.SourceAddrTon, e.b); err != nil {
+		return err
+	}
+	if err := e.writeInt8(&body.SourceAddrNpi, e.b); err != nil {
+		return err
+	}
+	return e.writeString(&body.SourceAddr, e.b)
+}
+
 // writeBindReceiver writes Bind Receiver smpp pdu
 func (e *Encoder) writeBindReceiver(pdu *BindReceiverPdu) error {
 	if err := e.writeBindBody(pdu.Body); err != nil {
 		return err
 	}
-	return e.writeHeader(pdu.Header)
+	return e.writeHeader(pdu.Header, pdu.CommandID())
 }
 
 // writeBindReceiverResp writes Bind Receiver Resp smpp pdu
@@ -189,7 +658,10 @@ func (e *Encoder) writeBindReceiverResp(pdu *BindReceiverRespPdu) error {
 	if err := e.writeBindRespBody(pdu.Body); err != nil {
 		return err
 	}
-	return e.writeHeader(pdu.Header)
+	if err := e.writeTlvList(pdu.Tlv); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
 }
 
 // writeBindTransmitter writes Bind Transmitter smpp pdu
@@ -197,7 +669,7 @@ func (e *Encoder) writeBindTransmitter(pdu *BindTransmitterPdu) error {
 	if err := e.writeBindBody(pdu.Body); err != nil {
 		return err
 	}
-	return e.writeHeader(pdu.Header)
+	return e.writeHeader(pdu.Header, pdu.CommandID())
 }
 
 // writeBindTransmitterResp writes Bind Transmitter Resp smpp pdu
@@ -205,7 +677,10 @@ func (e *Encoder) writeBindTransmitterResp(pdu *BindTransmitterRespPdu) error {
 	if err := e.writeBindRespBody(pdu.Body); err != nil {
 		return err
 	}
-	return e.writeHeader(pdu.Header)
+	if err := e.writeTlvList(pdu.Tlv); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
 }
 
 // writeBindTransceiver writes Bind Transceiver smpp pdu
@@ -213,7 +688,7 @@ func (e *Encoder) writeBindTransceiver(pdu *BindTransceiverPdu) error {
 	if err := e.writeBindBody(pdu.Body); err != nil {
 		return err
 	}
-	return e.writeHeader(pdu.Header)
+	return e.writeHeader(pdu.Header, pdu.CommandID())
 }
 
 // writeBindTransceiverResp writes Bind Transceiver Resp smpp pdu
@@ -221,17 +696,20 @@ func (e *Encoder) writeBindTransceiverResp(pdu *BindTransceiverRespPdu) error {
 	if err := e.writeBindRespBody(pdu.Body); err != nil {
 		return err
 	}
-	return e.writeHeader(pdu.Header)
+	if err := e.writeTlvList(pdu.Tlv); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
 }
 
 // writeUnbind writes Unbind smpp pdu
 func (e *Encoder) writeUnbind(pdu *UnbindPdu) error {
-	return e.writeHeader(pdu.Header)
+	return e.writeHeader(pdu.Header, pdu.CommandID())
 }
 
 // writeUnbindResp writes Unbind Resp smpp pdu
 func (e *Encoder) writeUnbindResp(pdu *UnbindRespPdu) error {
-	return e.writeHeader(pdu.Header)
+	return e.writeHeader(pdu.Header, pdu.CommandID())
 }
 
 // writeOutBind writes Out Bind smpp pdu
@@ -239,7 +717,7 @@ func (e *Encoder) writeOutBind(pdu *OutBindPdu) error {
 	if err := e.writeOutBindBody(pdu.Body); err != nil {
 		return err
 	}
-	return e.writeHeader(pdu.Header)
+	return e.writeHeader(pdu.Header, pdu.CommandID())
 }
 
 // writeSubmitSm writes Submit Sm smpp pdu
@@ -247,7 +725,10 @@ func (e *Encoder) writeSubmitSm(pdu *SubmitSmPdu) error {
 	if err := e.writeSmBody(pdu.Body); err != nil {
 		return err
 	}
-	return e.writeHeader(pdu.Header)
+	if err := e.writeTlvList(pdu.Tlv); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
 }
 
 // writeSubmitSmResp Submit Sm Resp smpp pdu
@@ -255,7 +736,7 @@ func (e *Encoder) writeSubmitSmResp(pdu *SubmitSmRespPdu) error {
 	if err := e.writeSmRespBody(pdu.Body); err != nil {
 		return err
 	}
-	return e.writeHeader(pdu.Header)
+	return e.writeHeader(pdu.Header, pdu.CommandID())
 }
 
 // writeDeliverSm writes Deliver Sm smpp pdu
@@ -263,7 +744,10 @@ func (e *Encoder) writeDeliverSm(pdu *DeliverSmPdu) error {
 	if err := e.writeSmBody(pdu.Body); err != nil {
 		return err
 	}
-	return e.writeHeader(pdu.Header)
+	if err := e.writeTlvList(pdu.Tlv); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
 }
 
 // writeDeliverSmResp writes Deliver Sm Resp smpp pdu
@@ -271,126 +755,293 @@ func (e *Encoder) writeDeliverSmResp(pdu *DeliverSmRespPdu) error {
 	if err := e.writeSmRespBody(pdu.Body); err != nil {
 		return err
 	}
-	return e.writeHeader(pdu.Header)
+	return e.writeHeader(pdu.Header, pdu.CommandID())
+}
+
+// writeQuerySm writes Query Sm smpp pdu
+func (e *Encoder) writeQuerySm(pdu *QuerySmPdu) error {
+	if err := e.writeQuerySmBody(pdu.Body); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
+}
+
+// writeQuerySmResp writes Query Sm Resp smpp pdu
+func (e *Encoder) writeQuerySmResp(pdu *QuerySmRespPdu) error {
+	if err := e.writeQuerySmRespBody(pdu.Body); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
+}
+
+// writeReplaceSm writes Replace Sm smpp pdu
+func (e *Encoder) writeReplaceSm(pdu *ReplaceSmPdu) error {
+	if err := e.writeReplaceSmBody(pdu.Body); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
+}
+
+// writeReplaceSmResp writes Replace Sm Resp smpp pdu
+func (e *Encoder) writeReplaceSmResp(pdu *ReplaceSmRespPdu) error {
+	return e.writeHeader(pdu.Header, pdu.CommandID())
+}
+
+// writeCancelSm writes Cancel Sm smpp pdu
+func (e *Encoder) writeCancelSm(pdu *CancelSmPdu) error {
+	if err := e.writeCancelSmBody(pdu.Body); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
+}
+
+// writeCancelSmResp writes Cancel Sm Resp smpp pdu
+func (e *Encoder) writeCancelSmResp(pdu *CancelSmRespPdu) error {
+	return e.writeHeader(pdu.Header, pdu.CommandID())
+}
+
+// writeSubmitMulti writes Submit Multi smpp pdu
+func (e *Encoder) writeSubmitMulti(pdu *SubmitMultiPdu) error {
+	if err := e.writeSubmitMultiBody(pdu.Body); err != nil {
+		return err
+	}
+	if err := e.writeTlvList(pdu.Tlv); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
+}
+
+// writeSubmitMultiResp writes Submit Multi Resp smpp pdu
+func (e *Encoder) writeSubmitMultiResp(pdu *SubmitMultiRespPdu) error {
+	if err := e.writeSubmitMultiRespBody(pdu.Body); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
+}
+
+// writeDataSm writes Data Sm smpp pdu
+func (e *Encoder) writeDataSm(pdu *DataSmPdu) error {
+	if err := e.writeDataSmBody(pdu.Body); err != nil {
+		return err
+	}
+	if err := e.writeTlvList(pdu.Tlv); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
+}
+
+// writeDataSmResp writes Data Sm Resp smpp pdu
+func (e *Encoder) writeDataSmResp(pdu *DataSmRespPdu) error {
+	if err := e.writeSmRespBody(pdu.Body); err != nil {
+		return err
+	}
+	if err := e.writeTlvList(pdu.Tlv); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
+}
+
+// writeAlertNotification writes Alert Notification smpp pdu
+func (e *Encoder) writeAlertNotification(pdu *AlertNotificationPdu) error {
+	if err := e.writeAlertNotificationBody(pdu.Body); err != nil {
+		return err
+	}
+	if err := e.writeTlvList(pdu.Tlv); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
+}
+
+// writeBroadcastSm writes Broadcast Sm smpp pdu
+func (e *Encoder) writeBroadcastSm(pdu *BroadcastSmPdu) error {
+	if err := pdu.Tlv.require(broadcastSmTlvs...); err != nil {
+		return err
+	}
+	if err := e.writeBroadcastSmBody(pdu.Body); err != nil {
+		return err
+	}
+	if err := e.writeTlvList(pdu.Tlv); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
+}
+
+// writeBroadcastSmResp writes Broadcast Sm Resp smpp pdu
+func (e *Encoder) writeBroadcastSmResp(pdu *BroadcastSmRespPdu) error {
+	if err := e.writeSmRespBody(pdu.Body); err != nil {
+		return err
+	}
+	if err := e.writeTlvList(pdu.Tlv); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
+}
+
+// writeQueryBroadcastSm writes Query Broadcast Sm smpp pdu
+func (e *Encoder) writeQueryBroadcastSm(pdu *QueryBroadcastSmPdu) error {
+	if err := e.writeQuerySmBody(pdu.Body); err != nil {
+		return err
+	}
+	if err := e.writeTlvList(pdu.Tlv); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
+}
+
+// writeQueryBroadcastSmResp writes Query Broadcast Sm Resp smpp pdu
+func (e *Encoder) writeQueryBroadcastSmResp(pdu *QueryBroadcastSmRespPdu) error {
+	if err := e.writeSmRespBody(pdu.Body); err != nil {
+		return err
+	}
+	if err := e.writeTlvList(pdu.Tlv); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
+}
+
+// writeCancelBroadcastSm writes Cancel Broadcast Sm smpp pdu
+func (e *Encoder) writeCancelBroadcastSm(pdu *CancelBroadcastSmPdu) error {
+	if err := e.writeCancelBroadcastSmBody(pdu.Body); err != nil {
+		return err
+	}
+	if err := e.writeTlvList(pdu.Tlv); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
+}
+
+// writeCancelBroadcastSmResp writes Cancel Broadcast Sm Resp smpp pdu
+func (e *Encoder) writeCancelBroadcastSmResp(pdu *CancelBroadcastSmRespPdu) error {
+	return e.writeHeader(pdu.Header, pdu.CommandID())
 }
 
 // writeEnquireLink writes Enquire Link smpp pdu
 func (e *Encoder) writeEnquireLink(pdu *EnquireLinkPdu) error {
-	return e.writeHeader(pdu.Header)
+	return e.writeHeader(pdu.Header, pdu.CommandID())
 }
 
 // writeEnquireLinkResp writes Enquire Link Resp smpp pdu
 func (e *Encoder) writeEnquireLinkResp(pdu *EnquireLinkRespPdu) error {
-	return e.writeHeader(pdu.Header)
+	return e.writeHeader(pdu.Header, pdu.CommandID())
 }
 
 // writeGenericNack writes Generic Nac smpp pdu
 func (e *Encoder) writeGenericNack(pdu *GenericNackPdu) error {
-	return e.writeHeader(pdu.Header)
+	return e.writeHeader(pdu.Header, pdu.CommandID())
 }
 
-// Encode encodes smpp pdu
-func (e *Encoder) Encode(pdu interface{}) error {
+// writeRaw writes pdu with unknown command id
+func (e *Encoder) writeRaw(pdu *RawPdu) error {
+	if err := e.writeOctets(pdu.Body, e.b); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.Header, pdu.CommandID())
+}
+
+// writeCustom writes registered custom pdu
+func (e *Encoder) writeCustom(pdu Pdu) error {
+	entry, ok := e.registry.lookup(pdu.CommandID())
+	if !ok {
+		return ErrUnsupportedPdu
+	}
+	body, err := entry.codec.EncodeBody(pdu)
+	if err != nil {
+		return err
+	}
+	if err := e.writeOctets(body, e.b); err != nil {
+		return err
+	}
+	return e.writeHeader(pdu.GetHeader(), pdu.CommandID())
+}
+
+// encode encodes smpp pdu into header and body buffers
+func (e *Encoder) encode(pdu Pdu) error {
+	e.h.Reset()
+	e.b.Reset()
+	e.overflow = nil
+	if pdu == nil || pdu.GetHeader() == nil {
+		return ErrFieldMissing
+	}
+	var err error
 	switch p := pdu.(type) {
 	case *BindReceiverPdu:
-		if err := e.writeBindBody(p.Body); err != nil {
-			return err
-		}
-		if err := e.writeHeader(p.Header); err != nil {
-			return err
-		}
+		err = e.writeBindReceiver(p)
 	case *BindReceiverRespPdu:
-		if err := e.writeBindRespBody(p.Body); err != nil {
-			return err
-		}
-		if err := e.writeHeader(p.Header); err != nil {
-			return err
-		}
+		err = e.writeBindReceiverResp(p)
 	case *BindTransmitterPdu:
-		if err := e.writeBindBody(p.Body); err != nil {
-			return err
-		}
-		if err := e.writeHeader(p.Header); err != nil {
-			return err
-		}
+		err = e.writeBindTransmitter(p)
 	case *BindTransmitterRespPdu:
-		if err := e.writeBindRespBody(p.Body); err != nil {
-			return err
-		}
-		if err := e.writeHeader(p.Header); err != nil {
-			return err
-		}
+		err = e.writeBindTransmitterResp(p)
 	case *BindTransceiverPdu:
-		if err := e.writeBindBody(p.Body); err != nil {
-			return err
-		}
-		if err := e.writeHeader(p.Header); err != nil {
-			return err
-		}
+		err = e.writeBindTransceiver(p)
 	case *BindTransceiverRespPdu:
-		if err := e.writeBindRespBody(p.Body); err != nil {
-			return err
-		}
-		if err := e.writeHeader(p.Header); err != nil {
-			return err
-		}
+		err = e.writeBindTransceiverResp(p)
 	case *UnbindPdu:
-		if err := e.writeHeader(p.Header); err != nil {
-			return err
-		}
+		err = e.writeUnbind(p)
 	case *UnbindRespPdu:
-		if err := e.writeHeader(p.Header); err != nil {
-			return err
-		}
+		err = e.writeUnbindResp(p)
 	case *OutBindPdu:
-		if err := e.writeOutBindBody(p.Body); err != nil {
-			return err
-		}
-		if err := e.writeHeader(p.Header); err != nil {
-			return err
-		}
+		err = e.writeOutBind(p)
 	case *SubmitSmPdu:
-		if err := e.writeSmBody(p.Body); err != nil {
-			return err
-		}
-		if err := e.writeHeader(p.Header); err != nil {
-			return err
-		}
+		err = e.writeSubmitSm(p)
 	case *SubmitSmRespPdu:
-		if err := e.writeSmRespBody(p.Body); err != nil {
-			return err
-		}
-		if err := e.writeHeader(p.Header); err != nil {
-			return err
-		}
+		err = e.writeSubmitSmResp(p)
 	case *DeliverSmPdu:
-		if err := e.writeSmBody(p.Body); err != nil {
-			return err
-		}
-		if err := e.writeHeader(p.Header); err != nil {
-			return err
-		}
+		err = e.writeDeliverSm(p)
 	case *DeliverSmRespPdu:
-		if err := e.writeSmRespBody(p.Body); err != nil {
-			return err
-		}
-		if err := e.writeHeader(p.Header); err != nil {
-			return err
-		}
+		err = e.writeDeliverSmResp(p)
+	case *QuerySmPdu:
+		err = e.writeQuerySm(p)
+	case *QuerySmRespPdu:
+		err = e.writeQuerySmResp(p)
+	case *ReplaceSmPdu:
+		err = e.writeReplaceSm(p)
+	case *ReplaceSmRespPdu:
+		err = e.writeReplaceSmResp(p)
+	case *CancelSmPdu:
+		err = e.writeCancelSm(p)
+	case *CancelSmRespPdu:
+		err = e.writeCancelSmResp(p)
+	case *SubmitMultiPdu:
+		err = e.writeSubmitMulti(p)
+	case *SubmitMultiRespPdu:
+		err = e.writeSubmitMultiResp(p)
+	case *DataSmPdu:
+		err = e.writeDataSm(p)
+	case *DataSmRespPdu:
+		err = e.writeDataSmResp(p)
+	case *AlertNotificationPdu:
+		err = e.writeAlertNotification(p)
+	case *BroadcastSmPdu:
+		err = e.writeBroadcastSm(p)
+	case *BroadcastSmRespPdu:
+		err = e.writeBroadcastSmResp(p)
+	case *QueryBroadcastSmPdu:
+		err = e.writeQueryBroadcastSm(p)
+	case *QueryBroadcastSmRespPdu:
+		err = e.writeQueryBroadcastSmResp(p)
+	case *CancelBroadcastSmPdu:
+		err = e.writeCancelBroadcastSm(p)
+	case *CancelBroadcastSmRespPdu:
+		err = e.writeCancelBroadcastSmResp(p)
 	case *EnquireLinkPdu:
-		if err := e.writeHeader(p.Header); err != nil {
-			return err
-		}
+		err = e.writeEnquireLink(p)
 	case *EnquireLinkRespPdu:
-		if err := e.writeHeader(p.Header); err != nil {
-			return err
-		}
+		err = e.writeEnquireLinkResp(p)
 	case *GenericNackPdu:
-		if err := e.writeHeader(p.Header); err != nil {
-			return err
-		}
+		err = e.writeGenericNack(p)
+	case *RawPdu:
+		err = e.writeRaw(p)
 	default:
-		return ErrUnsupportedPdu
+		err = e.writeCustom(p)
+	}
+	return err
+}
+
+// Encode encodes smpp pdu
+func (e *Encoder) Encode(pdu Pdu) error {
+	if err := e.encode(pdu); err != nil {
+		return err
 	}
 	n, err := e.w.Write(e.h.Bytes())
 	if err != nil {
@@ -408,3 +1059,12 @@ func (e *Encoder) Encode(pdu interface{}) error {
 	}
 	return nil
 }
+
+// Append encodes smpp pdu appending it to dst
+func (e *Encoder) Append(dst []byte, pdu Pdu) ([]byte, error) {
+	if err := e.encode(pdu); err != nil {
+		return dst, err
+	}
+	dst = append(dst, e.h.Bytes()...)
+	return append(dst, e.b.Bytes()...), nil
+}
@@ -2,6 +2,7 @@ package smpp
 
 import (
 	"bytes"
+	"encoding/binary"
 	"testing"
 )
 
@@ -37,7 +38,7 @@ func BenchmarkEncoder_Encode(b *testing.B) {
 	b.ReportAllocs()
 	pdu := &SubmitSmPdu{
 		Header: &Header{
-			CommandID:      EnquireLink,
+			CommandID:      SubmitSm,
 			CommandStatus:  EsmeRbindFail,
 			SequenceNumber: 2,
 		},
@@ -59,7 +60,7 @@ func BenchmarkEncoder_Encode(b *testing.B) {
 			DataCoding:           0,
 			SmDefaultMessageID:   0,
 			SmLength:             0,
-			ShortMessage:         "",
+			ShortMessage:         nil,
 		},
 	}
 	buffer := &bytes.Buffer{}
@@ -74,7 +75,7 @@ func BenchmarkDecoder_Decode(b *testing.B) {
 	b.ReportAllocs()
 	pdu := &SubmitSmPdu{
 		Header: &Header{
-			CommandID:      EnquireLink,
+			CommandID:      SubmitSm,
 			CommandStatus:  EsmeRbindFail,
 			SequenceNumber: 2,
 		},
@@ -96,7 +97,7 @@ func BenchmarkDecoder_Decode(b *testing.B) {
 			DataCoding:           0,
 			SmDefaultMessageID:   0,
 			SmLength:             0,
-			ShortMessage:         "",
+			ShortMessage:         nil,
 		},
 	}
 	buffer := &bytes.Buffer{}
@@ -111,3 +112,651 @@ func BenchmarkDecoder_Decode(b *testing.B) {
 		}
 	}
 }
+
+func header(length, id, status, seq uint32) []byte {
+	p := make([]byte, 16)
+	binary.BigEndian.PutUint32(p[0:], length)
+	binary.BigEndian.PutUint32(p[4:], id)
+	binary.BigEndian.PutUint32(p[8:], status)
+	binary.BigEndian.PutUint32(p[12:], seq)
+	return p
+}
+
+func cstr(s string) []byte {
+	return append([]byte(s), 0)
+}
+
+func join(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func BenchmarkEncoder_Append(b *testing.B) {
+	b.ReportAllocs()
+	pdu := &SubmitSmPdu{Header: &Header{CommandID: SubmitSm, SequenceNumber: 1}, Body: smBodyFixture}
+	encoder := NewEncoder(nil)
+	buf := make([]byte, 0, 512)
+	for i := 0; i < b.N; i++ {
+		var err error
+		if buf, err = encoder.Append(buf[:0], pdu); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecoder_DecodeInto(b *testing.B) {
+	b.ReportAllocs()
+	wire := join(header(55, SubmitSm, 0, 6), smBodyWire)
+	pdu := &SubmitSmPdu{}
+	decoder := new(Decoder)
+	for i := 0; i < b.N; i++ {
+		decoder.ResetBytes(wire)
+		if err := decoder.DecodeInto(pdu); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEncoder_Reuse(t *testing.T) {
+	encoder := NewEncoder(nil)
+	decoder := new(Decoder)
+	var buf []byte
+	for _, f := range encoderFixtures {
+		var err error
+		if buf, err = encoder.Append(buf[:0], f.pdu); err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		if !bytes.Equal(buf, f.wire) {
+			t.Fatalf("%s: encoded % x, want % x", f.name, buf, f.wire)
+		}
+		decoder.ResetBytes(buf)
+		if err := decoder.DecodeInto(newPdu(f.pdu.CommandID())); err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		encoder.Reset(nil)
+	}
+}
+
+func TestEncoder_ZeroAlloc(t *testing.T) {
+	pdu := &DeliverSmPdu{
+		Header: &Header{CommandID: DeliverSm, SequenceNumber: 7},
+		Body:   smBodyFixture,
+		Tlv:    TlvList{{Tag: ReceiptedMessageIdTlv, Value: cstr("abc123")}},
+	}
+	encoder := NewEncoder(nil)
+	buf := make([]byte, 0, 512)
+	if n := testing.AllocsPerRun(10, func() { buf, _ = encoder.Append(buf[:0], pdu) }); n != 0 {
+		t.Fatalf("encode allocs %v", n)
+	}
+	wire := append([]byte(nil), buf...)
+	into := &DeliverSmPdu{}
+	decoder := new(Decoder)
+	if n := testing.AllocsPerRun(10, func() {
+		decoder.ResetBytes(wire)
+		if err := decoder.DecodeInto(into); err != nil {
+			t.Fatal(err)
+		}
+	}); n != 0 {
+		t.Fatalf("decode allocs %v", n)
+	}
+	if !bytes.Equal(into.Body.ShortMessage, smBodyFixture.ShortMessage) || into.Body.SourceAddr != "Sender" {
+		t.Fatalf("unexpected pdu %+v", into.Body)
+	}
+}
+
+// loopReader replays b endlessly
+type loopReader struct {
+	b   []byte
+	off int
+}
+
+func (r *loopReader) Read(p []byte) (int, error) {
+	n := copy(p, r.b[r.off:])
+	r.off = (r.off + n) % len(r.b)
+	return n, nil
+}
+
+func TestDecoder_ZeroAllocStream(t *testing.T) {
+	pdu := &DeliverSmPdu{
+		Header: &Header{CommandID: DeliverSm, SequenceNumber: 7},
+		Body:   smBodyFixture,
+		Tlv:    TlvList{{Tag: ReceiptedMessageIdTlv, Value: cstr("abc123")}},
+	}
+	wire, err := pdu.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoder := NewStreamDecoder(NewReader(&loopReader{b: wire}))
+	into := &DeliverSmPdu{}
+	if n := testing.AllocsPerRun(10, func() {
+		if err := decoder.DecodeInto(into); err != nil {
+			t.Fatal(err)
+		}
+	}); n != 0 {
+		t.Fatalf("stream decode allocs %v", n)
+	}
+	if !bytes.Equal(into.Body.ShortMessage, smBodyFixture.ShortMessage) || into.SequenceNumber() != 7 {
+		t.Fatalf("unexpected pdu %+v", into.Body)
+	}
+}
+
+func TestDecoder_ZeroAllocSubmitMulti(t *testing.T) {
+	for _, f := range encoderFixtures {
+		if f.name != "submit_multi" && f.name != "submit_multi_resp" {
+			continue
+		}
+		into := newPdu(f.pdu.CommandID())
+		decoder := new(Decoder)
+		if n := testing.AllocsPerRun(10, func() {
+			decoder.ResetBytes(f.wire)
+			if err := decoder.DecodeInto(into); err != nil {
+				t.Fatal(err)
+			}
+		}); n != 0 {
+			t.Fatalf("%s: decode allocs %v", f.name, n)
+		}
+		if b, err := into.MarshalBinary(); err != nil || !bytes.Equal(b, f.wire) {
+			t.Fatalf("%s: decoded %s, want %s", f.name, into, f.pdu)
+		}
+	}
+}
+
+var bindBodyFixture = &BindBody{
+	SystemID:         "test",
+	Password:         "pass",
+	SystemType:       "VMA",
+	InterfaceVersion: ProtocolId,
+	AddrTon:          TonInternational,
+	AddrNpi:          NpiE164,
+	AddressRange:     "",
+}
+
+var bindBodyWire = join(cstr("test"), cstr("pass"), cstr("VMA"), []byte{0x34, 0x01, 0x01}, cstr(""))
+
+var smBodyFixture = &SmBody{
+	ServiceType:          "",
+	SourceAddrTon:        TonAlphanumeric,
+	SourceAddrNpi:        NpiUnknown,
+	SourceAddr:           "Sender",
+	DestAddrTon:          TonInternational,
+	DestAddrNpi:          NpiE164,
+	DestinationAddr:      "79001234567",
+	EsmClass:             EsmSubmitModeStoreAndForward,
+	ProtocolID:           0,
+	PriorityFlag:         PriorityFlag1,
+	ScheduleDeliveryTime: "",
+	ValidityPeriod:       "",
+	RegisteredDelivery:   RegDeliverySmscBoth,
+	ReplaceIfPresentFlag: ReplaceNo,
+	DataCoding:           DataCodingIso88591,
+	SmDefaultMessageID:   0,
+	SmLength:             5,
+	ShortMessage:         []byte("hello"),
+}
+
+var smBodyWire = join(
+	cstr(""), []byte{0x05, 0x00}, cstr("Sender"),
+	[]byte{0x01, 0x01}, cstr("79001234567"),
+	[]byte{0x03, 0x00, 0x01}, cstr(""), cstr(""),
+	[]byte{0x01, 0x00, 0x03, 0x00, 0x05}, []byte("hello"),
+)
+
+var encoderFixtures = []struct {
+	name string
+	pdu  Pdu
+	wire []byte
+}{
+	{
+		name: "bind_receiver",
+		pdu:  &BindReceiverPdu{Header: &Header{CommandID: BindReceiver, SequenceNumber: 1}, Body: bindBodyFixture},
+		wire: join(header(34, BindReceiver, 0, 1), bindBodyWire),
+	},
+	{
+		name: "bind_receiver_resp",
+		pdu: &BindReceiverRespPdu{
+			Header: &Header{CommandID: BindReceiverResp, SequenceNumber: 1},
+			Body:   &BindRespBody{SystemID: "smsc"},
+			Tlv:    TlvList{},
+		},
+		wire: join(header(21, BindReceiverResp, 0, 1), cstr("smsc")),
+	},
+	{
+		name: "bind_transmitter",
+		pdu:  &BindTransmitterPdu{Header: &Header{CommandID: BindTransmitter, SequenceNumber: 2}, Body: bindBodyFixture},
+		wire: join(header(34, BindTransmitter, 0, 2), bindBodyWire),
+	},
+	{
+		name: "bind_transmitter_resp",
+		pdu: &BindTransmitterRespPdu{
+			Header: &Header{CommandID: BindTransmitterResp, SequenceNumber: 2},
+			Body:   &BindRespBody{SystemID: "smsc"},
+			Tlv: TlvList{
+				{Tag: ScInterfaceVersionTlv, Value: []byte{0x34}},
+			},
+		},
+		wire: join(header(26, BindTransmitterResp, 0, 2), cstr("smsc"), []byte{0x02, 0x10, 0x00, 0x01, 0x34}),
+	},
+	{
+		name: "bind_transceiver",
+		pdu:  &BindTransceiverPdu{Header: &Header{CommandID: BindTransceiver, SequenceNumber: 3}, Body: bindBodyFixture},
+		wire: join(header(34, BindTransceiver, 0, 3), bindBodyWire),
+	},
+	{
+		name: "bind_transceiver_resp",
+		pdu: &BindTransceiverRespPdu{
+			Header: &Header{CommandID: BindTransceiverResp, CommandStatus: EsmeRbindFail, SequenceNumber: 3},
+			Body:   &BindRespBody{SystemID: ""},
+			Tlv:    TlvList{},
+		},
+		wire: join(header(17, BindTransceiverResp, EsmeRbindFail, 3), cstr("")),
+	},
+	{
+		name: "unbind",
+		pdu:  &UnbindPdu{Header: &Header{CommandID: Unbind, SequenceNumber: 4}},
+		wire: header(16, Unbind, 0, 4),
+	},
+	{
+		name: "unbind_resp",
+		pdu:  &UnbindRespPdu{Header: &Header{CommandID: UnbindResp, SequenceNumber: 4}},
+		wire: header(16, UnbindResp, 0, 4),
+	},
+	{
+		name: "outbind",
+		pdu:  &OutBindPdu{Header: &Header{CommandID: OutBind, SequenceNumber: 5}, Body: &OutBindBody{SystemID: "smsc", Password: "pass"}},
+		wire: join(header(26, OutBind, 0, 5), cstr("smsc"), cstr("pass")),
+	},
+	{
+		name: "submit_sm",
+		pdu: &SubmitSmPdu{
+			Header: &Header{CommandID: SubmitSm, SequenceNumber: 6},
+			Body:   smBodyFixture,
+			Tlv:    TlvList{},
+		},
+		wire: join(header(55, SubmitSm, 0, 6), smBodyWire),
+	},
+	{
+		name: "submit_sm_resp",
+		pdu:  &SubmitSmRespPdu{Header: &Header{CommandID: SubmitSmResp, SequenceNumber: 6}, Body: &SmRespBody{MessageID: "abc123"}},
+		wire: join(header(23, SubmitSmResp, 0, 6), cstr("abc123")),
+	},
+	{
+		name: "deliver_sm",
+		pdu: &DeliverSmPdu{
+			Header: &Header{CommandID: DeliverSm, SequenceNumber: 7},
+			Body:   smBodyFixture,
+			Tlv: TlvList{
+				{Tag: ReceiptedMessageIdTlv, Value: cstr("abc123")},
+			},
+		},
+		wire: join(header(66, DeliverSm, 0, 7), smBodyWire, []byte{0x00, 0x1E, 0x00, 0x07}, cstr("abc123")),
+	},
+	{
+		name: "deliver_sm_resp",
+		pdu:  &DeliverSmRespPdu{Header: &Header{CommandID: DeliverSmResp, SequenceNumber: 7}, Body: &SmRespBody{MessageID: ""}},
+		wire: join(header(17, DeliverSmResp, 0, 7), cstr("")),
+	},
+	{
+		name: "query_sm",
+		pdu: &QuerySmPdu{
+			Header: &Header{CommandID: QuerySm, SequenceNumber: 10},
+			Body:   &QuerySmBody{MessageID: "abc123", SourceAddrTon: TonInternational, SourceAddrNpi: NpiE164, SourceAddr: "79001234567"},
+		},
+		wire: join(header(37, QuerySm, 0, 10), cstr("abc123"), []byte{0x01, 0x01}, cstr("79001234567")),
+	},
+	{
+		name: "query_sm_resp",
+		pdu: &QuerySmRespPdu{
+			Header: &Header{CommandID: QuerySmResp, SequenceNumber: 10},
+			Body:   &QuerySmRespBody{MessageID: "abc123", FinalDate: "200101120000000+", MessageState: StateDelivered, ErrorCode: 0},
+		},
+		wire: join(header(42, QuerySmResp, 0, 10), cstr("abc123"), cstr("200101120000000+"), []byte{0x02, 0x00}),
+	},
+	{
+		name: "replace_sm",
+		pdu: &ReplaceSmPdu{
+			Header: &Header{CommandID: ReplaceSm, SequenceNumber: 11},
+			Body: &ReplaceSmBody{
+				MessageID:            "abc123",
+				SourceAddrTon:        TonAlphanumeric,
+				SourceAddrNpi:        NpiUnknown,
+				SourceAddr:           "Sender",
+				ScheduleDeliveryTime: "000001000000000R",
+				ValidityPeriod:       "",
+				RegisteredDelivery:   RegDeliverySmscBoth,
+				SmDefaultMessageID:   0,
+				SmLength:             3,
+				ShortMessage:         []byte("new"),
+			},
+		},
+		wire: join(
+			header(56, ReplaceSm, 0, 11), cstr("abc123"), []byte{0x05, 0x00}, cstr("Sender"),
+			cstr("000001000000000R"), cstr(""), []byte{0x01, 0x00, 0x03}, []byte("new"),
+		),
+	},
+	{
+		name: "replace_sm_resp",
+		pdu:  &ReplaceSmRespPdu{Header: &Header{CommandID: ReplaceSmResp, CommandStatus: EsmeRreplaceFail, SequenceNumber: 11}},
+		wire: header(16, ReplaceSmResp, EsmeRreplaceFail, 11),
+	},
+	{
+		name: "cancel_sm",
+		pdu: &CancelSmPdu{
+			Header: &Header{CommandID: CancelSm, SequenceNumber: 12},
+			Body: &CancelSmBody{
+				ServiceType:     "",
+				MessageID:       "abc123",
+				SourceAddrTon:   TonAlphanumeric,
+				SourceAddrNpi:   NpiUnknown,
+				SourceAddr:      "Sender",
+				DestAddrTon:     TonInternational,
+				DestAddrNpi:     NpiE164,
+				DestinationAddr: "79001234567",
+			},
+		},
+		wire: join(
+			header(47, CancelSm, 0, 12), cstr(""), cstr("abc123"), []byte{0x05, 0x00}, cstr("Sender"),
+			[]byte{0x01, 0x01}, cstr("79001234567"),
+		),
+	},
+	{
+		name: "cancel_sm_resp",
+		pdu:  &CancelSmRespPdu{Header: &Header{CommandID: CancelSmResp, SequenceNumber: 12}},
+		wire: header(16, CancelSmResp, 0, 12),
+	},
+	{
+		name: "submit_multi",
+		pdu: &SubmitMultiPdu{
+			Header: &Header{CommandID: SubmitMulti, SequenceNumber: 13},
+			Body: &SubmitMultiBody{
+				SourceAddrTon: TonAlphanumeric,
+				SourceAddr:    "Sender",
+				NumberOfDests: 2,
+				DestAddresses: []DestAddress{
+					{DestFlag: DestFlagSme, DestAddrTon: TonInternational, DestAddrNpi: NpiE164, DestinationAddr: "79001234567"},
+					{DestFlag: DestFlagDistlist, DlName: "staff"},
+				},
+				DataCoding:   DataCodingIso88591,
+				SmLength:     2,
+				ShortMessage: []byte("hi"),
+			},
+			Tlv: TlvList{},
+		},
+		wire: join(
+			header(61, SubmitMulti, 0, 13), cstr(""), []byte{0x05, 0x00}, cstr("Sender"), []byte{0x02},
+			[]byte{0x01, 0x01, 0x01}, cstr("79001234567"), []byte{0x02}, cstr("staff"),
+			[]byte{0x00, 0x00, 0x00}, cstr(""), cstr(""), []byte{0x00, 0x00, 0x03, 0x00, 0x02}, []byte("hi"),
+		),
+	},
+	{
+		name: "submit_multi_resp",
+		pdu: &SubmitMultiRespPdu{
+			Header: &Header{CommandID: SubmitMultiResp, SequenceNumber: 13},
+			Body: &SubmitMultiRespBody{
+				MessageID:   "abc123",
+				NoUnsuccess: 1,
+				UnsuccessSmes: []UnsuccessSme{
+					{DestAddrTon: TonInternational, DestAddrNpi: NpiE164, DestinationAddr: "79001234567", ErrorStatusCode: EsmeRinvDstAdr},
+				},
+			},
+		},
+		wire: join(
+			header(42, SubmitMultiResp, 0, 13), cstr("abc123"), []byte{0x01},
+			[]byte{0x01, 0x01}, cstr("79001234567"), []byte{0x00, 0x00, 0x00, 0x0B},
+		),
+	},
+	{
+		name: "data_sm",
+		pdu: &DataSmPdu{
+			Header: &Header{CommandID: DataSm, SequenceNumber: 14},
+			Body: &DataSmBody{
+				SourceAddrTon:      TonInternational,
+				SourceAddrNpi:      NpiE164,
+				SourceAddr:         "79001234567",
+				DestAddrTon:        TonInternational,
+				DestAddrNpi:        NpiE164,
+				DestinationAddr:    "79007654321",
+				RegisteredDelivery: RegDeliverySmscBoth,
+				DataCoding:         DataCodingBinary,
+			},
+			Tlv: TlvList{
+				{Tag: MessagePayloadTlv, Value: []byte{0x01, 0x00, 0x02}},
+			},
+		},
+		wire: join(
+			header(55, DataSm, 0, 14), cstr(""), []byte{0x01, 0x01}, cstr("79001234567"),
+			[]byte{0x01, 0x01}, cstr("79007654321"), []byte{0x00, 0x01, 0x04},
+			[]byte{0x04, 0x24, 0x00, 0x03, 0x01, 0x00, 0x02},
+		),
+	},
+	{
+		name: "data_sm_resp",
+		pdu: &DataSmRespPdu{
+			Header: &Header{CommandID: DataSmResp, CommandStatus: EsmeRdeliveryFailure, SequenceNumber: 14},
+			Body:   &SmRespBody{MessageID: ""},
+			Tlv: TlvList{
+				{Tag: DeliveryFailureReasonTlv, Value: []byte{0x03}},
+			},
+		},
+		wire: join(header(22, DataSmResp, EsmeRdeliveryFailure, 14), cstr(""), []byte{0x04, 0x25, 0x00, 0x01, 0x03}),
+	},
+	{
+		name: "alert_notification",
+		pdu: &AlertNotificationPdu{
+			Header: &Header{CommandID: AlertNotification, SequenceNumber: 15},
+			Body: &AlertNotificationBody{
+				SourceAddrTon: TonInternational,
+				SourceAddrNpi: NpiE164,
+				SourceAddr:    "79001234567",
+				EsmeAddrTon:   TonUnknown,
+				EsmeAddrNpi:   NpiUnknown,
+				EsmeAddr:      "1234",
+			},
+			Tlv: TlvList{
+				{Tag: MsAvailabilityStatusTlv, Value: []byte{0x00}},
+			},
+		},
+		wire: join(
+			header(42, AlertNotification, 0, 15), []byte{0x01, 0x01}, cstr("79001234567"),
+			[]byte{0x00, 0x00}, cstr("1234"), []byte{0x04, 0x22, 0x00, 0x01, 0x00},
+		),
+	},
+	{
+		name: "broadcast_sm",
+		pdu: &BroadcastSmPdu{
+			Header: &Header{CommandID: BroadcastSm, SequenceNumber: 16},
+			Body:   &BroadcastSmBody{SourceAddrTon: TonAlphanumeric, SourceAddr: "Alert"},
+			Tlv: TlvList{
+				{Tag: BroadcastAreaIdentifierTlv, Value: []byte{0x00, 'A', 'B'}},
+				{Tag: BroadcastContentTypeTlv, Value: []byte{0x01, 0x00, 0x01}},
+				{Tag: BroadcastRepNumTlv, Value: []byte{0x00, 0x03}},
+				{Tag: BroadcastFrequencyIntervalTlv, Value: []byte{0x09, 0x00, 0x05}},
+			},
+		},
+		wire: join(
+			header(59, BroadcastSm, 0, 16), cstr(""), []byte{0x05, 0x00}, cstr("Alert"), cstr(""),
+			[]byte{0x00}, cstr(""), cstr(""), []byte{0x00, 0x00, 0x00},
+			[]byte{0x06, 0x06, 0x00, 0x03, 0x00, 'A', 'B'},
+			[]byte{0x06, 0x01, 0x00, 0x03, 0x01, 0x00, 0x01},
+			[]byte{0x06, 0x04, 0x00, 0x02, 0x00, 0x03},
+			[]byte{0x06, 0x05, 0x00, 0x03, 0x09, 0x00, 0x05},
+		),
+	},
+	{
+		name: "broadcast_sm_resp",
+		pdu: &BroadcastSmRespPdu{
+			Header: &Header{CommandID: BroadcastSmResp, SequenceNumber: 16},
+			Body:   &SmRespBody{MessageID: "b1"},
+			Tlv:    TlvList{},
+		},
+		wire: join(header(19, BroadcastSmResp, 0, 16), cstr("b1")),
+	},
+	{
+		name: "query_broadcast_sm",
+		pdu: &QueryBroadcastSmPdu{
+			Header: &Header{CommandID: QueryBroadcastSm, SequenceNumber: 17},
+			Body:   &QuerySmBody{MessageID: "b1", SourceAddrTon: TonAlphanumeric, SourceAddr: "Alert"},
+			Tlv: TlvList{
+				{Tag: UserMessageReferenceTlv, Value: []byte{0x00, 0x07}},
+			},
+		},
+		wire: join(
+			header(33, QueryBroadcastSm, 0, 17), cstr("b1"), []byte{0x05, 0x00}, cstr("Alert"),
+			[]byte{0x02, 0x04, 0x00, 0x02, 0x00, 0x07},
+		),
+	},
+	{
+		name: "query_broadcast_sm_resp",
+		pdu: &QueryBroadcastSmRespPdu{
+			Header: &Header{CommandID: QueryBroadcastSmResp, SequenceNumber: 17},
+			Body:   &SmRespBody{MessageID: "b1"},
+			Tlv: TlvList{
+				{Tag: BroadcastAreaSuccessTlv, Value: []byte{0x64}},
+			},
+		},
+		wire: join(header(24, QueryBroadcastSmResp, 0, 17), cstr("b1"), []byte{0x06, 0x08, 0x00, 0x01, 0x64}),
+	},
+	{
+		name: "cancel_broadcast_sm",
+		pdu: &CancelBroadcastSmPdu{
+			Header: &Header{CommandID: CancelBroadcastSm, SequenceNumber: 18},
+			Body:   &CancelBroadcastSmBody{MessageID: "b1", SourceAddrTon: TonAlphanumeric, SourceAddr: "Alert"},
+			Tlv:    TlvList{},
+		},
+		wire: join(header(28, CancelBroadcastSm, 0, 18), cstr(""), cstr("b1"), []byte{0x05, 0x00}, cstr("Alert")),
+	},
+	{
+		name: "cancel_broadcast_sm_resp",
+		pdu:  &CancelBroadcastSmRespPdu{Header: &Header{CommandID: CancelBroadcastSmResp, SequenceNumber: 18}},
+		wire: header(16, CancelBroadcastSmResp, 0, 18),
+	},
+	{
+		name: "enquire_link",
+		pdu:  &EnquireLinkPdu{Header: &Header{CommandID: EnquireLink, SequenceNumber: 8}},
+		wire: header(16, EnquireLink, 0, 8),
+	},
+	{
+		name: "enquire_link_resp",
+		pdu:  &EnquireLinkRespPdu{Header: &Header{CommandID: EnquireLinkResp, SequenceNumber: 8}},
+		wire: header(16, EnquireLinkResp, 0, 8),
+	},
+	{
+		name: "generic_nack",
+		pdu:  &GenericNackPdu{Header: &Header{CommandID: GenericNack, CommandStatus: EsmeRinvCmdId, SequenceNumber: 9}},
+		wire: header(16, GenericNack, EsmeRinvCmdId, 9),
+	},
+}
+
+func TestEncoder_EncodeWire(t *testing.T) {
+	for _, f := range encoderFixtures {
+		buffer := &bytes.Buffer{}
+		if err := NewEncoder(buffer).Encode(f.pdu); err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		if !bytes.Equal(buffer.Bytes(), f.wire) {
+			t.Fatalf("%s: encoded % x, want % x", f.name, buffer.Bytes(), f.wire)
+		}
+		result, err := NewDecoder(buffer).Decode()
+		if err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		if buffer.Len() != 0 {
+			t.Fatalf("%s: %d bytes left after decode", f.name, buffer.Len())
+		}
+		again := &bytes.Buffer{}
+		if err := NewEncoder(again).Encode(result); err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		if !bytes.Equal(again.Bytes(), f.wire) {
+			t.Fatalf("%s: round trip % x, want % x", f.name, again.Bytes(), f.wire)
+		}
+	}
+}
+
+func TestEncoder_SmLength(t *testing.T) {
+	pdu := &SubmitSmPdu{
+		Header: &Header{CommandID: SubmitSm, SequenceNumber: 1},
+		Body:   &SmBody{ShortMessage: []byte("hello")},
+		Tlv:    TlvList{},
+	}
+	buffer := new(bytes.Buffer)
+	if err := NewEncoder(buffer).Encode(pdu); err != nil {
+		t.Fatal(err)
+	}
+	wire := join(
+		header(38, SubmitSm, 0, 1), cstr(""), []byte{0x00, 0x00}, cstr(""), []byte{0x00, 0x00}, cstr(""),
+		[]byte{0x00, 0x00, 0x00}, cstr(""), cstr(""), []byte{0x00, 0x00, 0x00, 0x00, 0x05}, []byte("hello"),
+	)
+	if !bytes.Equal(buffer.Bytes(), wire) {
+		t.Fatalf("encoded % x, want % x", buffer.Bytes(), wire)
+	}
+	if pdu.Body.SmLength != 0 {
+		t.Fatalf("encoder modified sm_length %d", pdu.Body.SmLength)
+	}
+
+	pdu.Body.ShortMessage = bytes.Repeat([]byte{'a'}, 300)
+	encoder := NewEncoder(new(bytes.Buffer))
+	if err := encoder.Encode(pdu); err != ErrEsmeRinvMsgLen {
+		t.Fatalf("unexpected error %v", err)
+	}
+	buffer = new(bytes.Buffer)
+	encoder.Reset(buffer)
+	encoder.SetMessagePayload(true)
+	if err := encoder.Encode(pdu); err != nil {
+		t.Fatal(err)
+	}
+	rep, err := NewDecoder(buffer).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	submit := rep.(*SubmitSmPdu)
+	if v, err := submit.Tlv.Bytes(MessagePayloadTlv); err != nil || !bytes.Equal(v, pdu.Body.ShortMessage) || submit.Body.SmLength != 0 || len(submit.Body.ShortMessage) != 0 {
+		t.Fatalf("unexpected message payload %d %v sm_length %d", len(v), err, submit.Body.SmLength)
+	}
+	if len(pdu.Tlv) != 0 {
+		t.Fatalf("encoder modified tlvs %+v", pdu.Tlv)
+	}
+	pdu.Tlv.Set(MessagePayloadTlv, []byte("x"))
+	if err := encoder.Encode(pdu); err != ErrEsmeRinvOptParamVal {
+		t.Fatalf("unexpected error %v", err)
+	}
+	replace := &ReplaceSmPdu{
+		Header: &Header{CommandID: ReplaceSm, SequenceNumber: 2},
+		Body:   &ReplaceSmBody{ShortMessage: pdu.Body.ShortMessage},
+	}
+	if err := encoder.Encode(replace); err != ErrEsmeRinvMsgLen {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestEncoder_Missing(t *testing.T) {
+	encoder := NewEncoder(new(bytes.Buffer))
+	if err := encoder.Encode(&EnquireLinkPdu{}); err != ErrFieldMissing {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := (&SubmitSmPdu{}).MarshalBinary(); err != ErrFieldMissing {
+		t.Fatalf("unexpected error %v", err)
+	}
+	pdu := &SubmitSmPdu{Header: &Header{CommandID: SubmitSm, SequenceNumber: 1}}
+	if err := encoder.Encode(pdu); err != ErrFieldMissing {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := pdu.MarshalBinary(); err != ErrFieldMissing {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestEncoder_CommandID(t *testing.T) {
+	pdu := &EnquireLinkPdu{Header: &Header{CommandID: SubmitSm, SequenceNumber: 1}}
+	b, err := pdu.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, header(16, EnquireLink, 0, 1)) {
+		t.Fatalf("encoded % x", b)
+	}
+	if pdu.Header.CommandID != SubmitSm {
+		t.Fatalf("encoder modified command id %x", pdu.Header.CommandID)
+	}
+	if err := pdu.Validate(); err != nil {
+		t.Fatalf("validation disagrees with encoder: %v", err)
+	}
+}
@@ -0,0 +1,834 @@
+package smpp
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnknownName throws when json names unknown command, status or tlv
+var ErrUnknownName = errors.New("unknown symbolic name")
+
+// pduJSON is json representation shared by every pdu
+type pduJSON struct {
+	CommandID      string          `json:"command_id"`
+	CommandStatus  string          `json:"command_status"`
+	SequenceNumber uint32          `json:"sequence_number"`
+	Body           json.RawMessage `json:"body,omitempty"`
+	// BodyOctets holds body strings which are not valid utf-8 keyed by field path
+	BodyOctets map[string][]byte `json:"body_octets,omitempty"`
+	Tlvs       []tlvJSON         `json:"tlvs,omitempty"`
+}
+
+// tlvJSON carries typed value of known tlv kinds or raw octets otherwise
+type tlvJSON struct {
+	Name   string          `json:"name"`
+	Value  json.RawMessage `json:"value,omitempty"`
+	Octets []byte          `json:"octets,omitempty"`
+}
+
+// DecodeJSON returns pdu of command named in json
+func DecodeJSON(data []byte) (Pdu, error) {
+	var v struct {
+		CommandID string `json:"command_id"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	id, err := parseName(v.CommandID, CommandNames)
+	if err != nil {
+		return nil, fmt.Errorf("command_id: %w", err)
+	}
+	pdu := newPdu(id)
+	if pdu == nil {
+		if entry, ok := DefaultRegistry.lookup(id); ok {
+			pdu = entry.factory()
+		} else {
+			pdu = &RawPdu{}
+		}
+	}
+	if err := unmarshalJSON(pdu, data); err != nil {
+		return nil, err
+	}
+	return pdu, nil
+}
+
+// marshalJSON renders pdu header with symbolic names, body fields and tlvs
+func marshalJSON(pdu Pdu) ([]byte, error) {
+	v := pduJSON{
+		CommandID:     CommandName(pdu.CommandID()),
+		CommandStatus: StatusName(EsmeRok),
+	}
+	if header := pdu.GetHeader(); header != nil {
+		v.CommandStatus = StatusName(header.CommandStatus)
+		v.SequenceNumber = header.SequenceNumber
+	}
+	if body := pduBody(pdu); body.IsValid() && !(body.Kind() == reflect.Ptr && body.IsNil()) {
+		b, err := json.Marshal(body.Interface())
+		if err != nil {
+			return nil, err
+		}
+		v.Body = b
+		v.BodyOctets = invalidStrings(body, "", nil)
+	}
+	if p, ok := pdu.(TlvPdu); ok {
+		for _, tlv := range *p.Tlvs() {
+			v.Tlvs = append(v.Tlvs, marshalTlv(tlv))
+		}
+	}
+	return json.Marshal(&v)
+}
+
+// unmarshalJSON fills pdu from json produced by marshalJSON
+func unmarshalJSON(pdu Pdu, data []byte) error {
+	var v pduJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	id, err := parseName(v.CommandID, CommandNames)
+	if err != nil {
+		return fmt.Errorf("command_id: %w", err)
+	}
+	status, err := parseName(v.CommandStatus, StatusNames)
+	if err != nil {
+		return fmt.Errorf("command_status: %w", err)
+	}
+	p := reflect.ValueOf(pdu)
+	if p.Kind() != reflect.Ptr || p.IsNil() || p.Elem().Kind() != reflect.Struct {
+		return ErrUnsupportedPdu
+	}
+	if _, raw := pdu.(*RawPdu); !raw && id != pdu.CommandID() {
+		return ErrUnsupportedPdu
+	}
+	header := p.Elem().FieldByName("Header")
+	if !header.IsValid() || header.Type() != reflect.TypeOf(&Header{}) {
+		return ErrUnsupportedPdu
+	}
+	header.Set(reflect.ValueOf(&Header{CommandID: id, CommandStatus: status, SequenceNumber: v.SequenceNumber}))
+	if body := pduBody(pdu); body.IsValid() {
+		value := reflect.New(body.Type())
+		if body.Kind() == reflect.Ptr {
+			value.Elem().Set(reflect.New(body.Type().Elem()))
+		}
+		if len(v.Body) > 0 {
+			if err := json.Unmarshal(v.Body, value.Interface()); err != nil {
+				return fmt.Errorf("body: %w", err)
+			}
+		}
+		for path, b := range v.BodyOctets {
+			if err := setString(value.Elem(), path, string(b)); err != nil {
+				return fmt.Errorf("body_octets %s: %w", path, err)
+			}
+		}
+		body.Set(value.Elem())
+	}
+	if tp, ok := pdu.(TlvPdu); ok {
+		list := tp.Tlvs()
+		*list = TlvList{}
+		for _, t := range v.Tlvs {
+			tlv, err := unmarshalTlv(t)
+			if err != nil {
+				return fmt.Errorf("tlv %s: %w", t.Name, err)
+			}
+			list.Add(tlv.Tag, tlv.Value)
+		}
+	} else if len(v.Tlvs) > 0 {
+		return ErrUnexpectedOctets
+	}
+	return nil
+}
+
+// pduBody returns settable body field of pdu struct
+func pduBody(pdu Pdu) reflect.Value {
+	v := reflect.ValueOf(pdu)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return v.Elem().FieldByName("Body")
+}
+
+// invalidStrings collects string fields of v which are not valid utf-8 keyed by
+// field path, json would replace their octets
+func invalidStrings(v reflect.Value, path string, out map[string][]byte) map[string][]byte {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return invalidStrings(v.Elem(), path, out)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if field := t.Field(i); field.PkgPath == "" {
+				out = invalidStrings(v.Field(i), fieldPath(path, jsonFieldName(field)), out)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; v.Type().Elem().Kind() != reflect.Uint8 && i < v.Len(); i++ {
+			out = invalidStrings(v.Index(i), fmt.Sprintf("%s[%d]", path, i), out)
+		}
+	case reflect.String:
+		if s := v.String(); !utf8.ValidString(s) {
+			if out == nil {
+				out = map[string][]byte{}
+			}
+			out[path] = []byte(s)
+		}
+	}
+	return out
+}
+
+// setString sets string field of v found by path built by invalidStrings
+func setString(v reflect.Value, path, s string) error {
+	for _, name := range strings.Split(path, ".") {
+		index := ""
+		if i := strings.IndexByte(name, '['); i >= 0 {
+			name, index = name[:i], name[i:]
+		}
+		for v.Kind() == reflect.Ptr && !v.IsNil() {
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return ErrUnknownName
+		}
+		field, ok := structField(v, name)
+		if !ok {
+			return ErrUnknownName
+		}
+		v = field
+		for index != "" {
+			end := strings.IndexByte(index, ']')
+			if end < 0 {
+				return ErrUnknownName
+			}
+			i, err := strconv.Atoi(index[1:end])
+			if err != nil || v.Kind() != reflect.Slice || i < 0 || i >= v.Len() {
+				return ErrUnknownName
+			}
+			v, index = v.Index(i), index[end+1:]
+		}
+	}
+	if v.Kind() != reflect.String || !v.CanSet() {
+		return ErrUnknownName
+	}
+	v.SetString(s)
+	return nil
+}
+
+// structField returns exported field of struct v by its json name
+func structField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		if field := t.Field(i); field.PkgPath == "" && jsonFieldName(field) == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// jsonFieldName returns name json gives to struct field
+func jsonFieldName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return field.Name
+}
+
+// fieldPath joins nested field name to path
+func fieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// marshalTlv renders tlv value typed when its octets match tlv definition
+func marshalTlv(tlv Tlv) tlvJSON {
+	name, ok := TlvNames[tlv.Tag]
+	if !ok {
+		name = fmt.Sprintf("0x%04X", tlv.Tag)
+	}
+	list := TlvList{tlv}
+	switch def := TlvDefs[tlv.Tag]; def.Kind {
+	case TlvInt8, TlvInt16, TlvInt32:
+		if n, err := list.Int(tlv.Tag); err == nil && len(tlv.Value) == tlvIntWidth(def.Kind) {
+			return tlvJSON{Name: name, Value: json.RawMessage(strconv.FormatUint(uint64(n), 10))}
+		}
+	case TlvCString:
+		// json would replace octets of invalid utf-8
+		if s, err := list.CString(tlv.Tag); err == nil && utf8.ValidString(s) {
+			if b, err := json.Marshal(s); err == nil {
+				return tlvJSON{Name: name, Value: b}
+			}
+		}
+	case TlvComposite:
+		if form, ok := tlvComposites[tlv.Tag]; ok {
+			v := form()
+			if v.decode(tlv.Value) {
+				if b, err := json.Marshal(v); err == nil {
+					return tlvJSON{Name: name, Value: b}
+				}
+			}
+		}
+	}
+	return tlvJSON{Name: name, Octets: tlv.Value}
+}
+
+// unmarshalTlv converts typed or raw json value back to tlv octets
+func unmarshalTlv(t tlvJSON) (Tlv, error) {
+	tag, err := parseName(t.Name, TlvNames)
+	if err != nil {
+		return Tlv{}, err
+	}
+	if len(t.Value) == 0 {
+		return Tlv{Tag: tag, Value: t.Octets}, nil
+	}
+	switch kind := TlvDefs[tag].Kind; kind {
+	case TlvInt8, TlvInt16, TlvInt32:
+		var n uint32
+		if err := json.Unmarshal(t.Value, &n); err != nil {
+			return Tlv{}, err
+		}
+		width := tlvIntWidth(kind)
+		if width < 4 && n>>(8*uint(width)) != 0 {
+			return Tlv{}, ErrEsmeRinvOptParamVal
+		}
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], n)
+		return Tlv{Tag: tag, Value: append([]byte(nil), b[4-width:]...)}, nil
+	case TlvCString:
+		var s string
+		if err := json.Unmarshal(t.Value, &s); err != nil {
+			return Tlv{}, err
+		}
+		return Tlv{Tag: tag, Value: append([]byte(s), 0)}, nil
+	case TlvComposite:
+		form, ok := tlvComposites[tag]
+		if !ok {
+			break
+		}
+		v := form()
+		if err := json.Unmarshal(t.Value, v); err != nil {
+			return Tlv{}, err
+		}
+		value, err := v.encode()
+		if err != nil {
+			return Tlv{}, err
+		}
+		return Tlv{Tag: tag, Value: value}, nil
+	}
+	return Tlv{}, ErrTlvKind
+}
+
+// tlvComposite is json struct form of composite tlv value
+type tlvComposite interface {
+	// decode fills struct from tlv value, false when value does not fit the form
+	decode(b []byte) bool
+	encode() ([]byte, error)
+}
+
+// tlvComposites constructs struct forms of composite tlvs
+var tlvComposites = map[uint32]func() tlvComposite{
+	CallbackNumTlv:                   func() tlvComposite { return new(callbackNumJSON) },
+	NetworkErrorCodeTlv:              func() tlvComposite { return new(networkErrorCodeJSON) },
+	ItsSessionInfoTlv:                func() tlvComposite { return new(itsSessionInfoJSON) },
+	BroadcastContentTypeTlv:          func() tlvComposite { return new(broadcastContentTypeJSON) },
+	BroadcastFrequencyIntervalTlv:    func() tlvComposite { return new(broadcastFrequencyIntervalJSON) },
+	BroadcastAreaIdentifierTlv:       func() tlvComposite { return new(broadcastAreaJSON) },
+	FailedBroadcastAreaIdentifierTlv: func() tlvComposite { return new(broadcastAreaJSON) },
+}
+
+// callbackNumJSON is callback_num form
+type callbackNumJSON struct {
+	DigitModeIndicator uint32 `json:"digit_mode_indicator"`
+	Ton                uint32 `json:"ton"`
+	Npi                uint32 `json:"npi"`
+	NumberDigits       string `json:"number_digits"`
+}
+
+func (v *callbackNumJSON) decode(b []byte) bool {
+	if len(b) < 4 {
+		return false
+	}
+	v.DigitModeIndicator, v.Ton, v.Npi = uint32(b[0]), uint32(b[1]), uint32(b[2])
+	v.NumberDigits = string(b[3:])
+	return utf8.ValidString(v.NumberDigits)
+}
+
+func (v *callbackNumJSON) encode() ([]byte, error) {
+	if v.DigitModeIndicator > 0xFF || v.Ton > 0xFF || v.Npi > 0xFF {
+		return nil, ErrEsmeRinvOptParamVal
+	}
+	return append([]byte{byte(v.DigitModeIndicator), byte(v.Ton), byte(v.Npi)}, v.NumberDigits...), nil
+}
+
+// networkErrorCodeJSON is network_error_code form
+type networkErrorCodeJSON struct {
+	NetworkType uint32 `json:"network_type"`
+	ErrorCode   uint32 `json:"error_code"`
+}
+
+func (v *networkErrorCodeJSON) decode(b []byte) bool {
+	var ok bool
+	v.NetworkType, v.ErrorCode, ok = compositeInt8Int16(b)
+	return ok
+}
+
+func (v *networkErrorCodeJSON) encode() ([]byte, error) {
+	return compositeBytesInt8Int16(v.NetworkType, v.ErrorCode)
+}
+
+// itsSessionInfoJSON is its_session_info form, end_of_session is lowest bit of sequence octet
+type itsSessionInfoJSON struct {
+	SessionNumber  uint32 `json:"session_number"`
+	SequenceNumber uint32 `json:"sequence_number"`
+	EndOfSession   bool   `json:"end_of_session"`
+}
+
+func (v *itsSessionInfoJSON) decode(b []byte) bool {
+	if len(b) != 2 {
+		return false
+	}
+	v.SessionNumber, v.SequenceNumber, v.EndOfSession = uint32(b[0]), uint32(b[1]>>1), b[1]&0x01 != 0
+	return true
+}
+
+func (v *itsSessionInfoJSON) encode() ([]byte, error) {
+	if v.SessionNumber > 0xFF || v.SequenceNumber > 0x7F {
+		return nil, ErrEsmeRinvOptParamVal
+	}
+	b := []byte{byte(v.SessionNumber), byte(v.SequenceNumber << 1)}
+	if v.EndOfSession {
+		b[1] |= 0x01
+	}
+	return b, nil
+}
+
+// broadcastContentTypeJSON is broadcast_content_type form
+type broadcastContentTypeJSON struct {
+	NetworkType uint32 `json:"network_type"`
+	ContentType uint32 `json:"content_type"`
+}
+
+func (v *broadcastContentTypeJSON) decode(b []byte) bool {
+	var ok bool
+	v.NetworkType, v.ContentType, ok = compositeInt8Int16(b)
+	return ok
+}
+
+func (v *broadcastContentTypeJSON) encode() ([]byte, error) {
+	return compositeBytesInt8Int16(v.NetworkType, v.ContentType)
+}
+
+// broadcastFrequencyIntervalJSON is broadcast_frequency_interval form
+type broadcastFrequencyIntervalJSON struct {
+	TimeUnit uint32 `json:"time_unit"`
+	Number   uint32 `json:"number"`
+}
+
+func (v *broadcastFrequencyIntervalJSON) decode(b []byte) bool {
+	var ok bool
+	v.TimeUnit, v.Number, ok = compositeInt8Int16(b)
+	return ok
+}
+
+func (v *broadcastFrequencyIntervalJSON) encode() ([]byte, error) {
+	return compositeBytesInt8Int16(v.TimeUnit, v.Number)
+}
+
+// broadcastAreaJSON is broadcast_area_identifier and failed_broadcast_area_identifier form
+type broadcastAreaJSON struct {
+	Format uint32 `json:"format"`
+	Area   []byte `json:"area"`
+}
+
+func (v *broadcastAreaJSON) decode(b []byte) bool {
+	if len(b) < 1 {
+		return false
+	}
+	v.Format, v.Area = uint32(b[0]), append([]byte(nil), b[1:]...)
+	return true
+}
+
+func (v *broadcastAreaJSON) encode() ([]byte, error) {
+	if v.Format > 0xFF {
+		return nil, ErrEsmeRinvOptParamVal
+	}
+	return append([]byte{byte(v.Format)}, v.Area...), nil
+}
+
+// compositeBytesInt8Int16 joins 1 octet and 2 octet integer pair
+func compositeBytesInt8Int16(a, b uint32) ([]byte, error) {
+	if a > 0xFF || b > 0xFFFF {
+		return nil, ErrEsmeRinvOptParamVal
+	}
+	return []byte{byte(a), byte(b >> 8), byte(b)}, nil
+}
+
+// tlvIntWidth returns octet width of integer tlv kind
+func tlvIntWidth(kind TlvKind) int {
+	switch kind {
+	case TlvInt8:
+		return 1
+	case TlvInt16:
+		return 2
+	}
+	return 4
+}
+
+// parseName resolves symbolic name or hex number to its code
+func parseName(name string, names map[uint32]string) (uint32, error) {
+	for code, n := range names {
+		if n == name {
+			return code, nil
+		}
+	}
+	if code, err := strconv.ParseUint(name, 0, 32); err == nil {
+		return uint32(code), nil
+	}
+	return 0, fmt.Errorf("%w %q", ErrUnknownName, name)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *BindReceiverPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *BindReceiverPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *BindReceiverRespPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *BindReceiverRespPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *BindTransmitterPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *BindTransmitterPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *BindTransmitterRespPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *BindTransmitterRespPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *BindTransceiverPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *BindTransceiverPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *BindTransceiverRespPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *BindTransceiverRespPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *SubmitSmPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *SubmitSmPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *SubmitSmRespPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *SubmitSmRespPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *DeliverSmPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *DeliverSmPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *DeliverSmRespPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *DeliverSmRespPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *QuerySmPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *QuerySmPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *QuerySmRespPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *QuerySmRespPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *ReplaceSmPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *ReplaceSmPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *ReplaceSmRespPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *ReplaceSmRespPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *CancelSmPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *CancelSmPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *CancelSmRespPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *CancelSmRespPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *SubmitMultiPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *SubmitMultiPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *SubmitMultiRespPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *SubmitMultiRespPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *DataSmPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *DataSmPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *DataSmRespPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *DataSmRespPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *AlertNotificationPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *AlertNotificationPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *BroadcastSmPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *BroadcastSmPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *BroadcastSmRespPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *BroadcastSmRespPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *QueryBroadcastSmPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *QueryBroadcastSmPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *QueryBroadcastSmRespPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *QueryBroadcastSmRespPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *CancelBroadcastSmPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *CancelBroadcastSmPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *CancelBroadcastSmRespPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *CancelBroadcastSmRespPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *EnquireLinkPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *EnquireLinkPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *EnquireLinkRespPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *EnquireLinkRespPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *GenericNackPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *GenericNackPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *UnbindPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *UnbindPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *UnbindRespPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *UnbindRespPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *OutBindPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *OutBindPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalJSON encodes pdu with symbolic names
+func (p *RawPdu) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON decodes pdu encoded by MarshalJSON
+func (p *RawPdu) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
@@ -0,0 +1,189 @@
+package smpp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJSON_RoundTrip(t *testing.T) {
+	for _, f := range encoderFixtures {
+		decoder := NewDecoder(bytes.NewBuffer(f.wire))
+		decoder.SetVersion(InterfaceVersion50)
+		pdu, err := decoder.Decode()
+		if err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		data, err := json.Marshal(pdu)
+		if err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		rep, err := DecodeJSON(data)
+		if err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		buffer := new(bytes.Buffer)
+		if err := NewEncoder(buffer).Encode(rep); err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		if !bytes.Equal(buffer.Bytes(), f.wire) {
+			t.Fatalf("%s: encoded % x, want % x\n%s", f.name, buffer.Bytes(), f.wire, data)
+		}
+	}
+}
+
+func TestJSON_Edit(t *testing.T) {
+	data := []byte(`{
+		"command_id": "submit_sm",
+		"command_status": "ESME_ROK",
+		"sequence_number": 7,
+		"body": {"source_addr": "Alert", "destination_addr": "79001234567", "data_coding": 8, "sm_length": 2, "short_message": "AEE="},
+		"tlvs": [
+			{"name": "user_message_reference", "value": 7},
+			{"name": "receipted_message_id", "value": "abc123"},
+			{"name": "0x1401", "octets": "AQ=="}
+		]
+	}`)
+	var pdu SubmitSmPdu
+	if err := json.Unmarshal(data, &pdu); err != nil {
+		t.Fatal(err)
+	}
+	wire := join(
+		header(73, SubmitSm, 0, 7), cstr(""), []byte{0x00, 0x00}, cstr("Alert"), []byte{0x00, 0x00}, cstr("79001234567"),
+		[]byte{0x00, 0x00, 0x00}, cstr(""), cstr(""), []byte{0x00, 0x00, 0x08, 0x00, 0x02, 0x00, 0x41},
+		[]byte{0x02, 0x04, 0x00, 0x02, 0x00, 0x07},
+		[]byte{0x00, 0x1E, 0x00, 0x07}, cstr("abc123"),
+		[]byte{0x14, 0x01, 0x00, 0x01, 0x01},
+	)
+	buffer := new(bytes.Buffer)
+	if err := NewEncoder(buffer).Encode(&pdu); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buffer.Bytes(), wire) {
+		t.Fatalf("encoded % x, want % x", buffer.Bytes(), wire)
+	}
+	out, err := json.Marshal(&pdu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"command_id":"submit_sm"`, `"short_message":"AEE="`, `{"name":"user_message_reference","value":7}`, `{"name":"0x1401","octets":"AQ=="}`} {
+		if !strings.Contains(string(out), want) {
+			t.Fatalf("json misses %s: %s", want, out)
+		}
+	}
+	if err := json.Unmarshal([]byte(`{"command_id":"submit_sm","command_status":"ESME_RNOPE"}`), &pdu); !errors.Is(err, ErrUnknownName) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"command_id":"deliver_sm","command_status":"ESME_ROK"}`), &pdu); err != ErrUnsupportedPdu {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestJSON_CompositeTlv(t *testing.T) {
+	for tag, def := range TlvDefs {
+		if _, ok := tlvComposites[tag]; def.Kind == TlvComposite && !ok {
+			t.Fatalf("%s has no json form", TlvNames[tag])
+		}
+	}
+	tlvs := TlvList{
+		{Tag: CallbackNumTlv, Value: join([]byte{0x01, 0x01, 0x01}, []byte("79001234567"))},
+		{Tag: NetworkErrorCodeTlv, Value: []byte{0x03, 0x00, 0x22}},
+		{Tag: ItsSessionInfoTlv, Value: []byte{0x05, 0x07}},
+		{Tag: BroadcastContentTypeTlv, Value: []byte{0x01, 0x00, 0x11}},
+		{Tag: BroadcastFrequencyIntervalTlv, Value: []byte{0x09, 0x00, 0x0F}},
+		{Tag: BroadcastAreaIdentifierTlv, Value: []byte{0x00, 'a', 'r', 'e', 'a'}},
+		{Tag: FailedBroadcastAreaIdentifierTlv, Value: []byte{0x02}},
+		{Tag: BroadcastRepNumTlv, Value: []byte{0x00, 0x03}},
+		{Tag: NetworkErrorCodeTlv, Value: []byte{0x03, 0x00}},
+	}
+	pdu := &DeliverSmPdu{Header: &Header{CommandID: DeliverSm, SequenceNumber: 1}, Body: &SmBody{}, Tlv: tlvs}
+	data, err := json.Marshal(pdu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`{"name":"callback_num","value":{"digit_mode_indicator":1,"ton":1,"npi":1,"number_digits":"79001234567"}}`,
+		`{"name":"network_error_code","value":{"network_type":3,"error_code":34}}`,
+		`{"name":"its_session_info","value":{"session_number":5,"sequence_number":3,"end_of_session":true}}`,
+		`{"name":"broadcast_content_type","value":{"network_type":1,"content_type":17}}`,
+		`{"name":"broadcast_frequency_interval","value":{"time_unit":9,"number":15}}`,
+		`{"name":"broadcast_area_identifier","value":{"format":0,"area":"YXJlYQ=="}}`,
+		`{"name":"broadcast_rep_num","value":3}`,
+		`{"name":"network_error_code","octets":"AwA="}`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("json misses %s: %s", want, data)
+		}
+	}
+	rep, err := DecodeJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := rep.(*DeliverSmPdu).Tlv
+	if len(got) != len(tlvs) {
+		t.Fatalf("decoded %d tlvs, want %d", len(got), len(tlvs))
+	}
+	for i := range tlvs {
+		if got[i].Tag != tlvs[i].Tag || !bytes.Equal(got[i].Value, tlvs[i].Value) {
+			t.Fatalf("tlv %d decoded %+v, want %+v", i, got[i], tlvs[i])
+		}
+	}
+	bad := []byte(`{"command_id":"deliver_sm","command_status":"ESME_ROK","tlvs":[{"name":"network_error_code","value":{"network_type":256}}]}`)
+	if _, err := DecodeJSON(bad); !errors.Is(err, ErrEsmeRinvOptParamVal) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestJSON_Latin1(t *testing.T) {
+	pdu := &SubmitMultiPdu{
+		Header: &Header{CommandID: SubmitMulti, SequenceNumber: 3},
+		Body: &SubmitMultiBody{
+			SourceAddr:    "M\xfcller",
+			NumberOfDests: 2,
+			DestAddresses: []DestAddress{
+				{DestFlag: DestFlagSme, DestinationAddr: "79001234567"},
+				{DestFlag: DestFlagDistlist, DlName: "caf\xe9"},
+			},
+			DataCoding:   0x03,
+			ShortMessage: []byte("\xe9t\xe9"),
+		},
+		Tlv: TlvList{
+			{Tag: CallbackNumTlv, Value: []byte{0x01, 0x01, 0x01, '7', 0xff}},
+		},
+	}
+	wire, err := pdu.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(pdu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"dest_address[1].dl_name":"Y2Fm6Q=="`) || !strings.Contains(string(data), `{"name":"callback_num","octets":"AQEBN/8="}`) {
+		t.Fatalf("invalid utf-8 not kept as octets: %s", data)
+	}
+	rep, err := DecodeJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := rep.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, wire) {
+		t.Fatalf("encoded % x, want % x\n%s", b, wire, data)
+	}
+	data = []byte(`{"command_id":"deliver_sm","command_status":"ESME_ROK","tlvs":[{"name":"receipted_message_id","octets":"6XTpAA=="}]}`)
+	rep, err = DecodeJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := json.Marshal(rep); err != nil || !strings.Contains(string(out), `{"name":"receipted_message_id","octets":"6XTpAA=="}`) {
+		t.Fatalf("c-octet string tlv not kept as octets: %s %v", out, err)
+	}
+	if err := json.Unmarshal([]byte(`{"command_id":"submit_sm","command_status":"ESME_ROK","body_octets":{"no_such_field":"AA=="}}`), new(SubmitSmPdu)); !errors.Is(err, ErrUnknownName) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,287 @@
+package pcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/DeathHand/smpp"
+)
+
+// DefaultPort is IANA registered smpp port
+const DefaultPort uint16 = 2775
+
+// ErrTruncated throws when snaplen cut tcp payload so stream lost octets
+var ErrTruncated = errors.New("tcp payload truncated by snaplen")
+
+// maxPending limits out of order segments buffered per stream direction
+const maxPending = 1024
+
+// Direction tells which peer sent pdu
+type Direction int
+
+const (
+	// ClientToServer is sent to smpp port, typically esme to smsc
+	ClientToServer Direction = iota
+	// ServerToClient is sent from smpp port, typically smsc to esme
+	ServerToClient
+)
+
+// String returns direction name
+func (d Direction) String() string {
+	if d == ServerToClient {
+		return "server_to_client"
+	}
+	return "client_to_server"
+}
+
+// Message is smpp pdu extracted from capture
+type Message struct {
+	// Timestamp is capture time of packet completing pdu
+	Timestamp time.Time
+	Direction Direction
+	Src, Dst  *net.TCPAddr
+	// Wire holds pdu octets as sent
+	Wire []byte
+	// Pdu is nil when Err is set
+	Pdu smpp.Pdu
+	Err error
+}
+
+// flowKey identifies tcp connection by its server and client endpoints
+type flowKey struct {
+	client, server         [16]byte
+	clientPort, serverPort uint16
+}
+
+// stream is one direction of tcp connection
+type stream struct {
+	init    bool
+	broken  bool
+	next    uint32
+	pending map[uint32][]byte
+	buf     []byte
+}
+
+// conn is reassembled tcp connection sharing decoder so negotiated version applies both ways
+type conn struct {
+	streams [2]stream
+	input   bytes.Buffer
+	decoder *smpp.Decoder
+}
+
+// Extractor reassembles tcp streams of smpp ports and decodes pdus
+type Extractor struct {
+	ports      map[uint16]bool
+	mode       smpp.DecodeMode
+	maxPduSize uint32
+	conns      map[flowKey]*conn
+}
+
+// NewExtractor constructs Extractor for given server ports, DefaultPort when none given
+func NewExtractor(ports ...uint16) *Extractor {
+	if len(ports) == 0 {
+		ports = []uint16{DefaultPort}
+	}
+	e := &Extractor{
+		ports:      map[uint16]bool{},
+		mode:       smpp.DecodeLenient,
+		maxPduSize: smpp.DefaultMaxPduSize,
+		conns:      map[flowKey]*conn{},
+	}
+	for _, port := range ports {
+		e.ports[port] = true
+	}
+	return e
+}
+
+// SetMode selects decoding mode, captures are decoded leniently by default
+func (e *Extractor) SetMode(mode smpp.DecodeMode) {
+	e.mode = mode
+}
+
+// SetMaxPduSize limits command_length accepted before stream is considered out of sync
+func (e *Extractor) SetMaxPduSize(size uint32) {
+	e.maxPduSize = size
+}
+
+// Add feeds captured packet and returns pdus it completes
+func (e *Extractor) Add(packet *Packet) []*Message {
+	s, ok := parseSegment(packet.LinkType, packet.Data)
+	if !ok {
+		return nil
+	}
+	var key flowKey
+	var direction Direction
+	switch {
+	case e.ports[s.dport]:
+		direction = ClientToServer
+		copy(key.client[:], s.src.To16())
+		copy(key.server[:], s.dst.To16())
+		key.clientPort, key.serverPort = s.sport, s.dport
+	case e.ports[s.sport]:
+		direction = ServerToClient
+		copy(key.client[:], s.dst.To16())
+		copy(key.server[:], s.src.To16())
+		key.clientPort, key.serverPort = s.dport, s.sport
+	default:
+		return nil
+	}
+	c, ok := e.conns[key]
+	if !ok {
+		c = &conn{}
+		c.decoder = smpp.NewDecoder(&c.input)
+		c.decoder.SetMode(e.mode)
+		e.conns[key] = c
+	}
+	st := &c.streams[direction]
+	if s.flags&tcpSyn != 0 {
+		*st = stream{init: true, next: s.seq + 1}
+		if direction == ClientToServer {
+			// new connection starts with default version
+			c.decoder.SetVersion(smpp.InterfaceVersion50)
+		}
+		return nil
+	}
+	var messages []*Message
+	if s.truncated {
+		messages = append(messages, &Message{Err: ErrTruncated})
+		st.broken = true
+	}
+	if !st.broken {
+		st.add(s.seq, s.payload)
+		messages = append(messages, e.frames(c, st)...)
+	}
+	for _, m := range messages {
+		m.Timestamp = packet.Timestamp
+		m.Direction = direction
+		m.Src = &net.TCPAddr{IP: s.src, Port: int(s.sport)}
+		m.Dst = &net.TCPAddr{IP: s.dst, Port: int(s.dport)}
+	}
+	if s.flags&(tcpFin|tcpRst) != 0 {
+		*st = stream{}
+		if s.flags&tcpRst != 0 || !c.streams[1-direction].init {
+			delete(e.conns, key)
+		}
+	}
+	return messages
+}
+
+// add appends in order payload and buffers segments arriving ahead of it
+func (st *stream) add(seq uint32, payload []byte) {
+	if !st.init {
+		// capture started mid connection
+		st.init = true
+		st.next = seq
+	}
+	if len(payload) == 0 {
+		return
+	}
+	if diff := int32(seq - st.next); diff > 0 {
+		if st.pending == nil {
+			st.pending = map[uint32][]byte{}
+		}
+		if len(st.pending) >= maxPending {
+			// gap is never filled, stream is lost until reconnect
+			st.pending = nil
+			st.broken = true
+			return
+		}
+		st.pending[seq] = append([]byte(nil), payload...)
+		return
+	}
+	st.append(seq, payload)
+	for len(st.pending) > 0 {
+		progress := false
+		for seq, payload := range st.pending {
+			if int32(seq-st.next) <= 0 {
+				delete(st.pending, seq)
+				st.append(seq, payload)
+				progress = true
+			}
+		}
+		if !progress {
+			break
+		}
+	}
+}
+
+// append appends payload not yet seen, dropping retransmitted octets
+func (st *stream) append(seq uint32, payload []byte) {
+	seen := int(st.next - seq)
+	if seen >= len(payload) {
+		return
+	}
+	st.buf = append(st.buf, payload[seen:]...)
+	st.next += uint32(len(payload) - seen)
+}
+
+// frames decodes complete pdus buffered in stream
+func (e *Extractor) frames(c *conn, st *stream) []*Message {
+	var messages []*Message
+	for uint32(len(st.buf)) >= smpp.PduHeaderLength {
+		length := binary.BigEndian.Uint32(st.buf)
+		if length < smpp.PduHeaderLength || length > e.maxPduSize {
+			// stream is out of sync, pdu boundaries are lost until reconnect
+			messages = append(messages, &Message{Wire: st.buf, Err: &smpp.FrameError{
+				CommandLength:  length,
+				CommandID:      binary.BigEndian.Uint32(st.buf[4:]),
+				SequenceNumber: binary.BigEndian.Uint32(st.buf[12:]),
+			}})
+			st.buf = nil
+			st.pending = nil
+			st.broken = true
+			break
+		}
+		if uint32(len(st.buf)) < length {
+			break
+		}
+		m := &Message{Wire: append([]byte(nil), st.buf[:length]...)}
+		st.buf = st.buf[length:]
+		c.input.Reset()
+		c.input.Write(m.Wire)
+		m.Pdu, m.Err = c.decoder.Decode()
+		if m.Err != nil {
+			m.Pdu = nil
+		}
+		messages = append(messages, m)
+	}
+	if len(st.buf) == 0 {
+		st.buf = nil
+	}
+	return messages
+}
+
+// ReadAll extracts pdus of smpp ports from pcap or pcapng capture
+func ReadAll(r io.Reader, ports ...uint16) ([]*Message, error) {
+	reader, err := NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	extractor := NewExtractor(ports...)
+	var messages []*Message
+	for {
+		packet, err := reader.Next()
+		if err == io.EOF {
+			return messages, nil
+		}
+		if err != nil {
+			return messages, err
+		}
+		messages = append(messages, extractor.Add(packet)...)
+	}
+}
+
+// ReadFile extracts pdus of smpp ports from capture file
+func ReadFile(name string, ports ...uint16) ([]*Message, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadAll(f, ports...)
+}
@@ -0,0 +1,192 @@
+package pcap
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+// ether types
+const (
+	etherTypeIPv4 uint16 = 0x0800
+	etherTypeIPv6 uint16 = 0x86DD
+	etherTypeVlan uint16 = 0x8100
+	etherTypeQinQ uint16 = 0x88A8
+)
+
+// ip protocol numbers
+const (
+	protoTCP         = 6
+	protoHopByHop    = 0
+	protoRouting     = 43
+	protoFragment    = 44
+	protoDestOptions = 60
+)
+
+// tcp flags
+const (
+	tcpFin = 0x01
+	tcpSyn = 0x02
+	tcpRst = 0x04
+)
+
+// segment is tcp segment of captured packet
+type segment struct {
+	src, dst     net.IP
+	sport, dport uint16
+	seq          uint32
+	flags        uint8
+	payload      []byte
+	// truncated is set when snaplen cut segment payload
+	truncated bool
+}
+
+// parseSegment returns tcp segment carried by link layer frame
+func parseSegment(linkType uint32, data []byte) (*segment, bool) {
+	var etherType uint16
+	switch linkType {
+	case LinkTypeEthernet:
+		if len(data) < 14 {
+			return nil, false
+		}
+		etherType = binary.BigEndian.Uint16(data[12:])
+		data = data[14:]
+		for etherType == etherTypeVlan || etherType == etherTypeQinQ {
+			if len(data) < 4 {
+				return nil, false
+			}
+			etherType = binary.BigEndian.Uint16(data[2:])
+			data = data[4:]
+		}
+	case LinkTypeSll:
+		if len(data) < 16 {
+			return nil, false
+		}
+		etherType = binary.BigEndian.Uint16(data[14:])
+		data = data[16:]
+	case LinkTypeSll2:
+		if len(data) < 20 {
+			return nil, false
+		}
+		etherType = binary.BigEndian.Uint16(data)
+		data = data[20:]
+	case LinkTypeNull, LinkTypeLoop:
+		if len(data) < 4 {
+			return nil, false
+		}
+		// address family is host byte order for null and network byte order for loop
+		family := binary.LittleEndian.Uint32(data)
+		if linkType == LinkTypeLoop || family > 0xFFFF {
+			family = binary.BigEndian.Uint32(data)
+		}
+		switch family {
+		case 2:
+			etherType = etherTypeIPv4
+		case 10, 24, 28, 30:
+			etherType = etherTypeIPv6
+		}
+		data = data[4:]
+	case LinkTypeRaw, LinkTypeIPv4, LinkTypeIPv6:
+		if len(data) == 0 {
+			return nil, false
+		}
+		switch data[0] >> 4 {
+		case 4:
+			etherType = etherTypeIPv4
+		case 6:
+			etherType = etherTypeIPv6
+		}
+	}
+	switch etherType {
+	case etherTypeIPv4:
+		return parseIPv4(data)
+	case etherTypeIPv6:
+		return parseIPv6(data)
+	}
+	return nil, false
+}
+
+// parseIPv4 returns tcp segment of unfragmented ipv4 packet
+func parseIPv4(data []byte) (*segment, bool) {
+	if len(data) < 20 || data[0]>>4 != 4 {
+		return nil, false
+	}
+	headerLength := int(data[0]&0x0F) * 4
+	total := int(binary.BigEndian.Uint16(data[2:]))
+	if headerLength < 20 || total < headerLength || len(data) < headerLength {
+		return nil, false
+	}
+	// fragments are not reassembled, smpp peers rarely fragment tcp
+	if binary.BigEndian.Uint16(data[6:])&0x3FFF != 0 || data[9] != protoTCP {
+		return nil, false
+	}
+	truncated := len(data) < total
+	if !truncated {
+		// ethernet pads short frames
+		data = data[:total]
+	}
+	s, ok := parseTCP(data[headerLength:])
+	if !ok {
+		return nil, false
+	}
+	s.src = net.IP(append([]byte(nil), data[12:16]...))
+	s.dst = net.IP(append([]byte(nil), data[16:20]...))
+	s.truncated = truncated
+	return s, true
+}
+
+// parseIPv6 returns tcp segment of ipv6 packet skipping extension headers
+func parseIPv6(data []byte) (*segment, bool) {
+	if len(data) < 40 || data[0]>>4 != 6 {
+		return nil, false
+	}
+	total := 40 + int(binary.BigEndian.Uint16(data[4:]))
+	truncated := len(data) < total
+	if !truncated {
+		data = data[:total]
+	}
+	src := net.IP(append([]byte(nil), data[8:24]...))
+	dst := net.IP(append([]byte(nil), data[24:40]...))
+	next := data[6]
+	payload := data[40:]
+	for next != protoTCP {
+		switch next {
+		case protoHopByHop, protoRouting, protoDestOptions:
+			if len(payload) < 8 {
+				return nil, false
+			}
+			length := 8 + int(payload[1])*8
+			if len(payload) < length {
+				return nil, false
+			}
+			next = payload[0]
+			payload = payload[length:]
+		default:
+			// fragments and other protocols
+			return nil, false
+		}
+	}
+	s, ok := parseTCP(payload)
+	if !ok {
+		return nil, false
+	}
+	s.src, s.dst, s.truncated = src, dst, truncated
+	return s, true
+}
+
+// parseTCP returns tcp segment header fields and payload
+func parseTCP(data []byte) (*segment, bool) {
+	if len(data) < 20 {
+		return nil, false
+	}
+	offset := int(data[12]>>4) * 4
+	if offset < 20 || len(data) < offset {
+		return nil, false
+	}
+	return &segment{
+		sport:   binary.BigEndian.Uint16(data),
+		dport:   binary.BigEndian.Uint16(data[2:]),
+		seq:     binary.BigEndian.Uint32(data[4:]),
+		flags:   data[13],
+		payload: data[offset:],
+	}, true
+}
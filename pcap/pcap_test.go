@@ -0,0 +1,291 @@
+package pcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/DeathHand/smpp"
+)
+
+var (
+	esme = net.IPv4(10, 0, 0, 1).To4()
+	smsc = net.IPv4(10, 0, 0, 2).To4()
+	t0   = time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+)
+
+// segmentSpec describes captured tcp segment
+type segmentSpec struct {
+	toServer bool
+	seq      uint32
+	flags    uint8
+	payload  []byte
+}
+
+func tcpSegment(sport, dport uint16, s segmentSpec) []byte {
+	b := make([]byte, 20, 20+len(s.payload))
+	binary.BigEndian.PutUint16(b, sport)
+	binary.BigEndian.PutUint16(b[2:], dport)
+	binary.BigEndian.PutUint32(b[4:], s.seq)
+	b[12] = 5 << 4
+	b[13] = s.flags | 0x10
+	return append(b, s.payload...)
+}
+
+func ipv4(src, dst net.IP, tcp []byte) []byte {
+	b := make([]byte, 20, 20+len(tcp))
+	b[0] = 0x45
+	binary.BigEndian.PutUint16(b[2:], uint16(20+len(tcp)))
+	b[8] = 64
+	b[9] = protoTCP
+	copy(b[12:], src)
+	copy(b[16:], dst)
+	return append(b, tcp...)
+}
+
+func ipv6(src, dst net.IP, tcp []byte) []byte {
+	// hop-by-hop options header precedes tcp
+	b := make([]byte, 48, 48+len(tcp))
+	b[0] = 0x60
+	binary.BigEndian.PutUint16(b[4:], uint16(8+len(tcp)))
+	b[6] = protoHopByHop
+	b[7] = 64
+	copy(b[8:], src)
+	copy(b[24:], dst)
+	b[40] = protoTCP
+	return append(b, tcp...)
+}
+
+func ethernet(etherType uint16, payload []byte) []byte {
+	b := make([]byte, 14, 64)
+	binary.BigEndian.PutUint16(b[12:], etherType)
+	b = append(b, payload...)
+	for len(b) < 60 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+func sll(etherType uint16, payload []byte) []byte {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint16(b[14:], etherType)
+	return append(b, payload...)
+}
+
+func pcapFile(linkType uint32, packets [][]byte) []byte {
+	w := new(bytes.Buffer)
+	binary.Write(w, binary.LittleEndian, pcapMagicMicro)
+	binary.Write(w, binary.LittleEndian, [2]uint16{2, 4})
+	for _, v := range []uint32{0, 0, 65535, linkType} {
+		binary.Write(w, binary.LittleEndian, v)
+	}
+	for i, p := range packets {
+		ts := t0.Add(time.Duration(i) * time.Millisecond)
+		for _, v := range []uint32{uint32(ts.Unix()), uint32(ts.Nanosecond() / 1000), uint32(len(p)), uint32(len(p))} {
+			binary.Write(w, binary.LittleEndian, v)
+		}
+		w.Write(p)
+	}
+	return w.Bytes()
+}
+
+func pcapngBlock(w *bytes.Buffer, blockType uint32, body []byte) {
+	for len(body)%4 != 0 {
+		body = append(body, 0)
+	}
+	binary.Write(w, binary.BigEndian, blockType)
+	binary.Write(w, binary.BigEndian, uint32(12+len(body)))
+	w.Write(body)
+	binary.Write(w, binary.BigEndian, uint32(12+len(body)))
+}
+
+func pcapngFile(linkType uint16, packets [][]byte) []byte {
+	w := new(bytes.Buffer)
+	pcapngBlock(w, blockSectionHeader, []byte{0x1A, 0x2B, 0x3C, 0x4D, 0, 1, 0, 0, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})
+	// interface with nanosecond resolution option followed by end of options
+	idb := []byte{byte(linkType >> 8), byte(linkType), 0, 0, 0, 0, 0, 0, 0, 9, 0, 1, 9, 0, 0, 0, 0, 0, 0, 0}
+	pcapngBlock(w, blockInterface, idb)
+	for i, p := range packets {
+		ts := uint64(t0.Add(time.Duration(i) * time.Millisecond).UnixNano())
+		body := make([]byte, 20, 20+len(p))
+		binary.BigEndian.PutUint32(body[4:], uint32(ts>>32))
+		binary.BigEndian.PutUint32(body[8:], uint32(ts))
+		binary.BigEndian.PutUint32(body[12:], uint32(len(p)))
+		binary.BigEndian.PutUint32(body[16:], uint32(len(p)))
+		pcapngBlock(w, blockEnhancedPacket, append(body, p...))
+	}
+	return w.Bytes()
+}
+
+func wire(t *testing.T, pdu smpp.Pdu) []byte {
+	b, err := pdu.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func fixturePdus(t *testing.T) (bind, bindResp, submit, submitResp []byte) {
+	bind = wire(t, &smpp.BindTransceiverPdu{
+		Header: &smpp.Header{CommandID: smpp.BindTransceiver, SequenceNumber: 1},
+		Body:   &smpp.BindBody{SystemID: "esme", Password: "pass", InterfaceVersion: smpp.InterfaceVersion34},
+	})
+	bindResp = wire(t, &smpp.BindTransceiverRespPdu{
+		Header: &smpp.Header{CommandID: smpp.BindTransceiverResp, SequenceNumber: 1},
+		Body:   &smpp.BindRespBody{SystemID: "smsc"},
+		Tlv:    smpp.TlvList{},
+	})
+	submit = wire(t, &smpp.SubmitSmPdu{
+		Header: &smpp.Header{CommandID: smpp.SubmitSm, SequenceNumber: 2},
+		Body:   &smpp.SmBody{SourceAddr: "Alert", DestinationAddr: "79001234567", SmLength: 5, ShortMessage: []byte("hello")},
+		Tlv:    smpp.TlvList{},
+	})
+	submitResp = wire(t, &smpp.SubmitSmRespPdu{
+		Header: &smpp.Header{CommandID: smpp.SubmitSmResp, SequenceNumber: 2},
+		Body:   &smpp.SmRespBody{MessageID: "abc123"},
+	})
+	return
+}
+
+// conversation returns esme to smsc session with split, reordered and retransmitted segments
+func conversation(t *testing.T) ([]segmentSpec, [][]byte) {
+	bind, bindResp, submit, submitResp := fixturePdus(t)
+	c, s := uint32(1000), uint32(5000)
+	specs := []segmentSpec{
+		{toServer: true, seq: c, flags: tcpSyn},
+		{toServer: false, seq: s, flags: tcpSyn},
+		// bind split in two and second half captured first
+		{toServer: true, seq: c + 1 + 10, payload: bind[10:]},
+		{toServer: true, seq: c + 1, payload: bind[:10]},
+		{toServer: false, seq: s + 1, payload: bindResp},
+		// retransmitted bind tail
+		{toServer: true, seq: c + 1 + 10, payload: bind[10:]},
+		{toServer: true, seq: c + 1 + uint32(len(bind)), payload: submit},
+		{toServer: false, seq: s + 1 + uint32(len(bindResp)), payload: submitResp},
+		// unrelated port
+		{toServer: true, seq: 1, payload: []byte("GET / HTTP/1.1\r\n")},
+	}
+	return specs, [][]byte{bind, bindResp, submit, submitResp}
+}
+
+func checkMessages(t *testing.T, messages []*Message, want [][]byte, times []time.Time) {
+	if len(messages) != len(want) {
+		t.Fatalf("%d messages, want %d", len(messages), len(want))
+	}
+	directions := []Direction{ClientToServer, ServerToClient, ClientToServer, ServerToClient}
+	commands := []uint32{smpp.BindTransceiver, smpp.BindTransceiverResp, smpp.SubmitSm, smpp.SubmitSmResp}
+	for i, m := range messages {
+		if m.Err != nil {
+			t.Fatalf("message %d: %v", i, m.Err)
+		}
+		if m.Direction != directions[i] || m.Pdu.CommandID() != commands[i] {
+			t.Fatalf("message %d: %v %s", i, m.Direction, smpp.CommandName(m.Pdu.CommandID()))
+		}
+		if !bytes.Equal(m.Wire, want[i]) {
+			t.Fatalf("message %d: wire % x, want % x", i, m.Wire, want[i])
+		}
+		if !m.Timestamp.Equal(times[i]) {
+			t.Fatalf("message %d: timestamp %v, want %v", i, m.Timestamp, times[i])
+		}
+	}
+	if m := messages[0]; m.Src.Port != 40000 || m.Dst.Port != int(DefaultPort) {
+		t.Fatalf("unexpected endpoints %v %v", m.Src, m.Dst)
+	}
+}
+
+func TestReadAll_Pcap(t *testing.T) {
+	specs, want := conversation(t)
+	var packets [][]byte
+	for _, s := range specs {
+		sport, dport, src, dst := uint16(40000), DefaultPort, esme, smsc
+		if !s.toServer {
+			sport, dport, src, dst = dport, sport, dst, src
+		}
+		if s.seq == 1 {
+			dport = 80
+		}
+		packets = append(packets, ethernet(etherTypeIPv4, ipv4(src, dst, tcpSegment(sport, dport, s))))
+	}
+	messages, err := ReadAll(bytes.NewReader(pcapFile(LinkTypeEthernet, packets)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms := time.Millisecond
+	checkMessages(t, messages, want, []time.Time{t0.Add(3 * ms), t0.Add(4 * ms), t0.Add(6 * ms), t0.Add(7 * ms)})
+	if v := messages[2].Pdu.(*smpp.SubmitSmPdu).Body.ShortMessage; string(v) != "hello" {
+		t.Fatalf("short message %q", v)
+	}
+}
+
+func TestReadAll_Pcapng(t *testing.T) {
+	specs, want := conversation(t)
+	esme6, smsc6 := net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8::2")
+	var packets [][]byte
+	for _, s := range specs {
+		sport, dport, src, dst := uint16(40000), DefaultPort, esme6, smsc6
+		if !s.toServer {
+			sport, dport, src, dst = dport, sport, dst, src
+		}
+		if s.seq == 1 {
+			dport = 80
+		}
+		packets = append(packets, sll(etherTypeIPv6, ipv6(src, dst, tcpSegment(sport, dport, s))))
+	}
+	messages, err := ReadAll(bytes.NewReader(pcapngFile(uint16(LinkTypeSll), packets)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms := time.Millisecond
+	checkMessages(t, messages, want, []time.Time{t0.Add(3 * ms), t0.Add(4 * ms), t0.Add(6 * ms), t0.Add(7 * ms)})
+	if !messages[0].Src.IP.Equal(esme6) {
+		t.Fatalf("unexpected source %v", messages[0].Src)
+	}
+}
+
+func TestExtractor_LinkTypes(t *testing.T) {
+	_, _, submit, _ := fixturePdus(t)
+	ip := ipv4(esme, smsc, tcpSegment(40000, 2776, segmentSpec{seq: 1, payload: submit}))
+	tests := []struct {
+		linkType uint32
+		data     []byte
+	}{
+		{LinkTypeNull, append([]byte{2, 0, 0, 0}, ip...)},
+		{LinkTypeLoop, append([]byte{0, 0, 0, 2}, ip...)},
+		{LinkTypeRaw, ip},
+		{LinkTypeEthernet, ethernet(etherTypeVlan, append([]byte{0, 1, 0x08, 0x00}, ip...))},
+	}
+	for _, test := range tests {
+		messages := NewExtractor(2775, 2776).Add(&Packet{Timestamp: t0, LinkType: test.linkType, Data: test.data})
+		if len(messages) != 1 || messages[0].Err != nil || !bytes.Equal(messages[0].Wire, submit) {
+			t.Fatalf("link type %d: unexpected messages %+v", test.linkType, messages)
+		}
+	}
+}
+
+func TestExtractor_OutOfSync(t *testing.T) {
+	e := NewExtractor()
+	garbage := append([]byte{0, 0, 0, 4}, make([]byte, 12)...)
+	messages := e.Add(&Packet{LinkType: LinkTypeRaw, Data: ipv4(esme, smsc, tcpSegment(40000, DefaultPort, segmentSpec{seq: 1, payload: garbage}))})
+	var frameErr *smpp.FrameError
+	if len(messages) != 1 || !errors.As(messages[0].Err, &frameErr) || frameErr.CommandLength != 4 {
+		t.Fatalf("unexpected messages %+v", messages)
+	}
+	_, _, submit, _ := fixturePdus(t)
+	messages = e.Add(&Packet{LinkType: LinkTypeRaw, Data: ipv4(esme, smsc, tcpSegment(40000, DefaultPort, segmentSpec{seq: 17, payload: submit}))})
+	if len(messages) != 0 {
+		t.Fatalf("unexpected messages after desync %+v", messages)
+	}
+}
+
+func TestNewReader_Format(t *testing.T) {
+	if _, err := NewReader(bytes.NewReader([]byte("not a capture at all, really"))); err != ErrFormat {
+		t.Fatalf("unexpected error %v", err)
+	}
+	file := pcapngFile(uint16(LinkTypeRaw), [][]byte{{0x45}})
+	if _, err := ReadAll(bytes.NewReader(file[:len(file)-2])); err != ErrBlock {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,299 @@
+package pcap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrFormat throws when capture is neither pcap nor pcapng
+var ErrFormat = errors.New("unknown capture format")
+
+// ErrBlock throws on malformed pcap record or pcapng block
+var ErrBlock = errors.New("malformed capture block")
+
+// Link layer types - tcpdump.org/linktypes.html
+const (
+	LinkTypeNull     uint32 = 0
+	LinkTypeEthernet uint32 = 1
+	LinkTypeRaw      uint32 = 101
+	LinkTypeLoop     uint32 = 108
+	LinkTypeSll      uint32 = 113
+	LinkTypeIPv4     uint32 = 228
+	LinkTypeIPv6     uint32 = 229
+	LinkTypeSll2     uint32 = 276
+)
+
+// pcap file magics
+const (
+	pcapMagicMicro uint32 = 0xA1B2C3D4
+	pcapMagicNano  uint32 = 0xA1B23C4D
+)
+
+// pcapng block types
+const (
+	blockSectionHeader   uint32 = 0x0A0D0D0A
+	blockInterface       uint32 = 0x00000001
+	blockPacket          uint32 = 0x00000002
+	blockSimplePacket    uint32 = 0x00000003
+	blockEnhancedPacket  uint32 = 0x00000006
+	pcapngByteOrderMagic uint32 = 0x1A2B3C4D
+	optionTsResol        uint16 = 9
+	maxBlockLength       uint32 = 16 * 1024 * 1024
+)
+
+// Packet is a captured link layer frame
+type Packet struct {
+	Timestamp time.Time
+	LinkType  uint32
+	// Data holds captured octets, shorter than Length when cut by snaplen
+	Data   []byte
+	Length uint32
+}
+
+// iface is pcapng interface description
+type iface struct {
+	linkType uint32
+	snapLen  uint32
+	tsResol  uint8
+}
+
+// Reader reads packets of pcap or pcapng capture
+type Reader struct {
+	r     *bufio.Reader
+	ng    bool
+	order binary.ByteOrder
+	// pcap
+	linkType uint32
+	nano     bool
+	// pcapng
+	ifaces []iface
+}
+
+// NewReader constructs Reader detecting capture format by its magic
+func NewReader(r io.Reader) (*Reader, error) {
+	reader := &Reader{r: bufio.NewReader(r)}
+	magic, err := reader.r.Peek(4)
+	if err != nil {
+		return nil, ErrFormat
+	}
+	if binary.BigEndian.Uint32(magic) == blockSectionHeader {
+		reader.ng = true
+		if err := reader.readSectionHeader(); err != nil {
+			return nil, err
+		}
+		return reader, nil
+	}
+	header := make([]byte, 24)
+	if _, err := io.ReadFull(reader.r, header); err != nil {
+		return nil, ErrFormat
+	}
+	for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		switch order.Uint32(header) {
+		case pcapMagicMicro:
+			reader.order = order
+		case pcapMagicNano:
+			reader.order = order
+			reader.nano = true
+		}
+	}
+	if reader.order == nil {
+		return nil, ErrFormat
+	}
+	reader.linkType = reader.order.Uint32(header[20:]) & 0x0FFFFFFF
+	return reader, nil
+}
+
+// Next returns next packet or io.EOF at the end of capture
+func (r *Reader) Next() (*Packet, error) {
+	if r.ng {
+		return r.nextBlock()
+	}
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(r.r, header); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, ErrBlock
+		}
+		return nil, err
+	}
+	sec := r.order.Uint32(header)
+	frac := r.order.Uint32(header[4:])
+	captured := r.order.Uint32(header[8:])
+	if captured > maxBlockLength {
+		return nil, ErrBlock
+	}
+	packet := &Packet{LinkType: r.linkType, Data: make([]byte, captured), Length: r.order.Uint32(header[12:])}
+	if _, err := io.ReadFull(r.r, packet.Data); err != nil {
+		return nil, ErrBlock
+	}
+	if !r.nano {
+		frac *= 1000
+	}
+	packet.Timestamp = time.Unix(int64(sec), int64(frac)).UTC()
+	return packet, nil
+}
+
+// readBlock reads pcapng block returning its type and body
+func (r *Reader) readBlock() (uint32, []byte, error) {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(r.r, header); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return 0, nil, ErrBlock
+		}
+		return 0, nil, err
+	}
+	if binary.BigEndian.Uint32(header) == blockSectionHeader {
+		// section byte order is unknown until byte order magic is read
+		magic, err := r.r.Peek(4)
+		if err != nil {
+			return 0, nil, ErrBlock
+		}
+		switch binary.BigEndian.Uint32(magic) {
+		case pcapngByteOrderMagic:
+			r.order = binary.BigEndian
+		case 0x4D3C2B1A:
+			r.order = binary.LittleEndian
+		default:
+			return 0, nil, ErrFormat
+		}
+	}
+	blockType := r.order.Uint32(header)
+	length := r.order.Uint32(header[4:])
+	if length < 12 || length%4 != 0 || length > maxBlockLength {
+		return 0, nil, ErrBlock
+	}
+	body := make([]byte, length-8)
+	if _, err := io.ReadFull(r.r, body); err != nil {
+		return 0, nil, ErrBlock
+	}
+	if r.order.Uint32(body[len(body)-4:]) != length {
+		return 0, nil, ErrBlock
+	}
+	return blockType, body[:len(body)-4], nil
+}
+
+// readSectionHeader reads leading pcapng section header block
+func (r *Reader) readSectionHeader() error {
+	blockType, _, err := r.readBlock()
+	if err != nil {
+		return err
+	}
+	if blockType != blockSectionHeader {
+		return ErrFormat
+	}
+	return nil
+}
+
+// nextBlock reads pcapng blocks until packet block
+func (r *Reader) nextBlock() (*Packet, error) {
+	for {
+		blockType, body, err := r.readBlock()
+		if err != nil {
+			return nil, err
+		}
+		switch blockType {
+		case blockSectionHeader:
+			r.ifaces = r.ifaces[:0]
+		case blockInterface:
+			if len(body) < 8 {
+				return nil, ErrBlock
+			}
+			r.ifaces = append(r.ifaces, iface{
+				linkType: uint32(r.order.Uint16(body)),
+				snapLen:  r.order.Uint32(body[4:]),
+				tsResol:  r.tsResol(body[8:]),
+			})
+		case blockEnhancedPacket, blockPacket:
+			return r.readPacketBlock(blockType, body)
+		case blockSimplePacket:
+			if len(body) < 4 || len(r.ifaces) == 0 {
+				return nil, ErrBlock
+			}
+			packet := &Packet{LinkType: r.ifaces[0].linkType, Length: r.order.Uint32(body)}
+			// simple packet block is cut by snaplen of the first interface and padded
+			packet.Data = body[4:]
+			if snapLen := r.ifaces[0].snapLen; snapLen > 0 && uint32(len(packet.Data)) > snapLen {
+				packet.Data = packet.Data[:snapLen]
+			}
+			if uint32(len(packet.Data)) > packet.Length {
+				packet.Data = packet.Data[:packet.Length]
+			}
+			return packet, nil
+		}
+	}
+}
+
+// readPacketBlock reads enhanced or obsolete packet block
+func (r *Reader) readPacketBlock(blockType uint32, body []byte) (*Packet, error) {
+	if len(body) < 20 {
+		return nil, ErrBlock
+	}
+	id := r.order.Uint32(body)
+	if blockType == blockPacket {
+		id = uint32(r.order.Uint16(body))
+	}
+	if id >= uint32(len(r.ifaces)) {
+		return nil, ErrBlock
+	}
+	iface := r.ifaces[id]
+	captured := r.order.Uint32(body[12:])
+	if uint32(len(body)-20) < captured {
+		return nil, ErrBlock
+	}
+	ts := uint64(r.order.Uint32(body[4:]))<<32 | uint64(r.order.Uint32(body[8:]))
+	return &Packet{
+		Timestamp: timestamp(ts, iface.tsResol),
+		LinkType:  iface.linkType,
+		Data:      body[20 : 20+captured],
+		Length:    r.order.Uint32(body[16:]),
+	}, nil
+}
+
+// tsResol returns if_tsresol option of interface description, microseconds by default
+func (r *Reader) tsResol(options []byte) uint8 {
+	for len(options) >= 4 {
+		code := r.order.Uint16(options)
+		length := int(r.order.Uint16(options[2:]))
+		padded := (length + 3) &^ 3
+		if len(options) < 4+padded {
+			break
+		}
+		if code == optionTsResol && length == 1 {
+			return options[4]
+		}
+		if code == 0 {
+			break
+		}
+		options = options[4+padded:]
+	}
+	return 6
+}
+
+// timestamp converts pcapng timestamp units to time
+func timestamp(ts uint64, resol uint8) time.Time {
+	if resol&0x80 != 0 {
+		shift := uint(resol & 0x7F)
+		if shift > 63 {
+			return time.Unix(0, 0).UTC()
+		}
+		sec := ts >> shift
+		frac := ts & (1<<shift - 1)
+		if shift > 32 {
+			frac >>= shift - 32
+			shift = 32
+		}
+		return time.Unix(int64(sec), int64((frac*1e9)>>shift)).UTC()
+	}
+	units := uint64(1)
+	for i := uint8(0); i < resol && i < 19; i++ {
+		units *= 10
+	}
+	sec := ts / units
+	frac := ts % units
+	if units >= 1e9 {
+		return time.Unix(int64(sec), int64(frac/(units/1e9))).UTC()
+	}
+	return time.Unix(int64(sec), int64(frac*(1e9/units))).UTC()
+}
@@ -1,62 +1,223 @@
 package smpp
 
+import (
+	"bytes"
+	"encoding"
+)
+
+// Pdu is implemented by every smpp pdu
+type Pdu interface {
+	GetHeader() *Header
+	CommandID() uint32
+	SequenceNumber() uint32
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// TlvPdu is implemented by pdus carrying optional parameters
+type TlvPdu interface {
+	Pdu
+	Tlvs() *TlvList
+}
+
+// Validator is implemented by pdus checking their fields against spec,
+// every standard pdu implements it, Encoder.Validate also honours encoder settings
+type Validator interface {
+	Validate() error
+}
+
 type Header struct {
 	CommandLength  uint32
 	CommandID      uint32
 	CommandStatus  uint32
 	SequenceNumber uint32
+	// Warnings are deviations tolerated by lenient decoding
+	Warnings []*FieldError
 }
 
 type Tlv struct {
 	Tag    uint32
 	Length uint32
-	Value  string
+	Value  []byte
 }
 
-type TlvMap map[string]Tlv
+// TlvList is an ordered tlv collection keeping wire order and duplicate tags
+type TlvList []Tlv
 
 type BindBody struct {
-	SystemID         string
-	Password         string
-	SystemType       string
-	InterfaceVersion uint32
-	AddrTon          uint32
-	AddrNpi          uint32
-	AddressRange     string
+	SystemID         string `json:"system_id"`
+	Password         string `json:"password"`
+	SystemType       string `json:"system_type"`
+	InterfaceVersion uint32 `json:"interface_version"`
+	AddrTon          uint32 `json:"addr_ton"`
+	AddrNpi          uint32 `json:"addr_npi"`
+	AddressRange     string `json:"address_range"`
 }
 
 type OutBindBody struct {
-	SystemID string
-	Password string
+	SystemID string `json:"system_id"`
+	Password string `json:"password"`
 }
 
 type BindRespBody struct {
-	SystemID string
+	SystemID string `json:"system_id"`
 }
 
+// MaxShortMessageLength is maximal short_message length shared by encoder and validation
+// SMPP v3.4 - 5.2.22 page 128
+const MaxShortMessageLength = 254
+
 type SmBody struct {
-	ServiceType          string
-	SourceAddrTon        uint32
-	SourceAddrNpi        uint32
-	SourceAddr           string
-	DestAddrTon          uint32
-	DestAddrNpi          uint32
-	DestinationAddr      string
-	EsmClass             uint32
-	ProtocolID           uint32
-	PriorityFlag         uint32
-	ScheduleDeliveryTime string
-	ValidityPeriod       string
-	RegisteredDelivery   uint32
-	ReplaceIfPresentFlag uint32
-	DataCoding           uint32
-	SmDefaultMessageID   uint32
-	SmLength             uint32
-	ShortMessage         string
+	ServiceType          string `json:"service_type"`
+	SourceAddrTon        uint32 `json:"source_addr_ton"`
+	SourceAddrNpi        uint32 `json:"source_addr_npi"`
+	SourceAddr           string `json:"source_addr"`
+	DestAddrTon          uint32 `json:"dest_addr_ton"`
+	DestAddrNpi          uint32 `json:"dest_addr_npi"`
+	DestinationAddr      string `json:"destination_addr"`
+	EsmClass             uint32 `json:"esm_class"`
+	ProtocolID           uint32 `json:"protocol_id"`
+	PriorityFlag         uint32 `json:"priority_flag"`
+	ScheduleDeliveryTime string `json:"schedule_delivery_time"`
+	ValidityPeriod       string `json:"validity_period"`
+	RegisteredDelivery   uint32 `json:"registered_delivery"`
+	ReplaceIfPresentFlag uint32 `json:"replace_if_present_flag"`
+	DataCoding           uint32 `json:"data_coding"`
+	SmDefaultMessageID   uint32 `json:"sm_default_msg_id"`
+	SmLength             uint32 `json:"sm_length"` // derived from ShortMessage when encoding, zero is allowed
+	ShortMessage         []byte `json:"short_message"`
 }
 
 type SmRespBody struct {
-	MessageID string
+	MessageID string `json:"message_id"`
+}
+
+type QuerySmBody struct {
+	MessageID     string `json:"message_id"`
+	SourceAddrTon uint32 `json:"source_addr_ton"`
+	SourceAddrNpi uint32 `json:"source_addr_npi"`
+	SourceAddr    string `json:"source_addr"`
+}
+
+type QuerySmRespBody struct {
+	MessageID    string `json:"message_id"`
+	FinalDate    string `json:"final_date"`
+	MessageState uint32 `json:"message_state"`
+	ErrorCode    uint32 `json:"error_code"`
+}
+
+type ReplaceSmBody struct {
+	MessageID            string `json:"message_id"`
+	SourceAddrTon        uint32 `json:"source_addr_ton"`
+	SourceAddrNpi        uint32 `json:"source_addr_npi"`
+	SourceAddr           string `json:"source_addr"`
+	ScheduleDeliveryTime string `json:"schedule_delivery_time"`
+	ValidityPeriod       string `json:"validity_period"`
+	RegisteredDelivery   uint32 `json:"registered_delivery"`
+	SmDefaultMessageID   uint32 `json:"sm_default_msg_id"`
+	SmLength             uint32 `json:"sm_length"` // derived from ShortMessage when encoding, zero is allowed
+	ShortMessage         []byte `json:"short_message"`
+}
+
+type CancelSmBody struct {
+	ServiceType     string `json:"service_type"`
+	MessageID       string `json:"message_id"`
+	SourceAddrTon   uint32 `json:"source_addr_ton"`
+	SourceAddrNpi   uint32 `json:"source_addr_npi"`
+	SourceAddr      string `json:"source_addr"`
+	DestAddrTon     uint32 `json:"dest_addr_ton"`
+	DestAddrNpi     uint32 `json:"dest_addr_npi"`
+	DestinationAddr string `json:"destination_addr"`
+}
+
+type DestAddress struct {
+	DestFlag        uint32 `json:"dest_flag"`
+	DestAddrTon     uint32 `json:"dest_addr_ton"`
+	DestAddrNpi     uint32 `json:"dest_addr_npi"`
+	DestinationAddr string `json:"destination_addr"`
+	DlName          string `json:"dl_name"`
+}
+
+type SubmitMultiBody struct {
+	ServiceType          string        `json:"service_type"`
+	SourceAddrTon        uint32        `json:"source_addr_ton"`
+	SourceAddrNpi        uint32        `json:"source_addr_npi"`
+	SourceAddr           string        `json:"source_addr"`
+	NumberOfDests        uint32        `json:"number_of_dests"`
+	DestAddresses        []DestAddress `json:"dest_address"`
+	EsmClass             uint32        `json:"esm_class"`
+	ProtocolID           uint32        `json:"protocol_id"`
+	PriorityFlag         uint32        `json:"priority_flag"`
+	ScheduleDeliveryTime string        `json:"schedule_delivery_time"`
+	ValidityPeriod       string        `json:"validity_period"`
+	RegisteredDelivery   uint32        `json:"registered_delivery"`
+	ReplaceIfPresentFlag uint32        `json:"replace_if_present_flag"`
+	DataCoding           uint32        `json:"data_coding"`
+	SmDefaultMessageID   uint32        `json:"sm_default_msg_id"`
+	SmLength             uint32        `json:"sm_length"` // derived from ShortMessage when encoding, zero is allowed
+	ShortMessage         []byte        `json:"short_message"`
+}
+
+type UnsuccessSme struct {
+	DestAddrTon     uint32 `json:"dest_addr_ton"`
+	DestAddrNpi     uint32 `json:"dest_addr_npi"`
+	DestinationAddr string `json:"destination_addr"`
+	ErrorStatusCode uint32 `json:"error_status_code"`
+}
+
+// Err returns error matching unsuccess sme error status code
+func (u *UnsuccessSme) Err() error {
+	return Err(u.ErrorStatusCode)
+}
+
+type SubmitMultiRespBody struct {
+	MessageID     string         `json:"message_id"`
+	NoUnsuccess   uint32         `json:"no_unsuccess"`
+	UnsuccessSmes []UnsuccessSme `json:"unsuccess_sme"`
+}
+
+type DataSmBody struct {
+	ServiceType        string `json:"service_type"`
+	SourceAddrTon      uint32 `json:"source_addr_ton"`
+	SourceAddrNpi      uint32 `json:"source_addr_npi"`
+	SourceAddr         string `json:"source_addr"`
+	DestAddrTon        uint32 `json:"dest_addr_ton"`
+	DestAddrNpi        uint32 `json:"dest_addr_npi"`
+	DestinationAddr    string `json:"destination_addr"`
+	EsmClass           uint32 `json:"esm_class"`
+	RegisteredDelivery uint32 `json:"registered_delivery"`
+	DataCoding         uint32 `json:"data_coding"`
+}
+
+type AlertNotificationBody struct {
+	SourceAddrTon uint32 `json:"source_addr_ton"`
+	SourceAddrNpi uint32 `json:"source_addr_npi"`
+	SourceAddr    string `json:"source_addr"`
+	EsmeAddrTon   uint32 `json:"esme_addr_ton"`
+	EsmeAddrNpi   uint32 `json:"esme_addr_npi"`
+	EsmeAddr      string `json:"esme_addr"`
+}
+
+type BroadcastSmBody struct {
+	ServiceType          string `json:"service_type"`
+	SourceAddrTon        uint32 `json:"source_addr_ton"`
+	SourceAddrNpi        uint32 `json:"source_addr_npi"`
+	SourceAddr           string `json:"source_addr"`
+	MessageID            string `json:"message_id"`
+	PriorityFlag         uint32 `json:"priority_flag"`
+	ScheduleDeliveryTime string `json:"schedule_delivery_time"`
+	ValidityPeriod       string `json:"validity_period"`
+	ReplaceIfPresentFlag uint32 `json:"replace_if_present_flag"`
+	DataCoding           uint32 `json:"data_coding"`
+	SmDefaultMessageID   uint32 `json:"sm_default_msg_id"`
+}
+
+type CancelBroadcastSmBody struct {
+	ServiceType   string `json:"service_type"`
+	MessageID     string `json:"message_id"`
+	SourceAddrTon uint32 `json:"source_addr_ton"`
+	SourceAddrNpi uint32 `json:"source_addr_npi"`
+	SourceAddr    string `json:"source_addr"`
 }
 
 type BindReceiverPdu struct {
@@ -67,7 +228,7 @@ type BindReceiverPdu struct {
 type BindReceiverRespPdu struct {
 	Header *Header
 	Body   *BindRespBody
-	Tlv    TlvMap
+	Tlv    TlvList
 }
 
 type BindTransmitterPdu struct {
@@ -78,7 +239,7 @@ type BindTransmitterPdu struct {
 type BindTransmitterRespPdu struct {
 	Header *Header
 	Body   *BindRespBody
-	Tlv    TlvMap
+	Tlv    TlvList
 }
 
 type BindTransceiverPdu struct {
@@ -89,13 +250,13 @@ type BindTransceiverPdu struct {
 type BindTransceiverRespPdu struct {
 	Header *Header
 	Body   *BindRespBody
-	Tlv    TlvMap
+	Tlv    TlvList
 }
 
 type SubmitSmPdu struct {
 	Header *Header
 	Body   *SmBody
-	Tlv    TlvMap
+	Tlv    TlvList
 }
 
 type SubmitSmRespPdu struct {
@@ -106,7 +267,7 @@ type SubmitSmRespPdu struct {
 type DeliverSmPdu struct {
 	Header *Header
 	Body   *SmBody
-	Tlv    TlvMap
+	Tlv    TlvList
 }
 
 type DeliverSmRespPdu struct {
@@ -114,6 +275,97 @@ type DeliverSmRespPdu struct {
 	Body   *SmRespBody
 }
 
+type QuerySmPdu struct {
+	Header *Header
+	Body   *QuerySmBody
+}
+
+type QuerySmRespPdu struct {
+	Header *Header
+	Body   *QuerySmRespBody
+}
+
+type ReplaceSmPdu struct {
+	Header *Header
+	Body   *ReplaceSmBody
+}
+
+type ReplaceSmRespPdu struct {
+	Header *Header
+}
+
+type CancelSmPdu struct {
+	Header *Header
+	Body   *CancelSmBody
+}
+
+type CancelSmRespPdu struct {
+	Header *Header
+}
+
+type SubmitMultiPdu struct {
+	Header *Header
+	Body   *SubmitMultiBody
+	Tlv    TlvList
+}
+
+type SubmitMultiRespPdu struct {
+	Header *Header
+	Body   *SubmitMultiRespBody
+}
+
+type DataSmPdu struct {
+	Header *Header
+	Body   *DataSmBody
+	Tlv    TlvList
+}
+
+type DataSmRespPdu struct {
+	Header *Header
+	Body   *SmRespBody
+	Tlv    TlvList
+}
+
+type AlertNotificationPdu struct {
+	Header *Header
+	Body   *AlertNotificationBody
+	Tlv    TlvList
+}
+
+type BroadcastSmPdu struct {
+	Header *Header
+	Body   *BroadcastSmBody
+	Tlv    TlvList
+}
+
+type BroadcastSmRespPdu struct {
+	Header *Header
+	Body   *SmRespBody
+	Tlv    TlvList
+}
+
+type QueryBroadcastSmPdu struct {
+	Header *Header
+	Body   *QuerySmBody
+	Tlv    TlvList
+}
+
+type QueryBroadcastSmRespPdu struct {
+	Header *Header
+	Body   *SmRespBody
+	Tlv    TlvList
+}
+
+type CancelBroadcastSmPdu struct {
+	Header *Header
+	Body   *CancelBroadcastSmBody
+	Tlv    TlvList
+}
+
+type CancelBroadcastSmRespPdu struct {
+	Header *Header
+}
+
 type EnquireLinkPdu struct {
 	Header *Header
 }
@@ -138,3 +390,916 @@ type OutBindPdu struct {
 	Header *Header
 	Body   *OutBindBody
 }
+
+// sequenceNumber returns header sequence number, zero for missing header
+func sequenceNumber(header *Header) uint32 {
+	if header == nil {
+		return 0
+	}
+	return header.SequenceNumber
+}
+
+// marshal encodes pdu to bytes
+func marshal(pdu Pdu) ([]byte, error) {
+	return NewEncoder(nil).Append(nil, pdu)
+}
+
+// unmarshal decodes bytes into pdu
+func unmarshal(pdu Pdu, data []byte) error {
+	return NewDecoder(bytes.NewBuffer(data)).DecodeInto(pdu)
+}
+
+// GetHeader returns pdu header
+func (p *BindReceiverPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *BindReceiverPdu) CommandID() uint32 {
+	return BindReceiver
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *BindReceiverPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *BindReceiverPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *BindReceiverPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// GetHeader returns pdu header
+func (p *BindReceiverRespPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *BindReceiverRespPdu) CommandID() uint32 {
+	return BindReceiverResp
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *BindReceiverRespPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *BindReceiverRespPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *BindReceiverRespPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// Tlvs returns pdu optional parameters
+func (p *BindReceiverRespPdu) Tlvs() *TlvList {
+	return &p.Tlv
+}
+
+// GetHeader returns pdu header
+func (p *BindTransmitterPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *BindTransmitterPdu) CommandID() uint32 {
+	return BindTransmitter
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *BindTransmitterPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *BindTransmitterPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *BindTransmitterPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// GetHeader returns pdu header
+func (p *BindTransmitterRespPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *BindTransmitterRespPdu) CommandID() uint32 {
+	return BindTransmitterResp
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *BindTransmitterRespPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *BindTransmitterRespPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *BindTransmitterRespPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// Tlvs returns pdu optional parameters
+func (p *BindTransmitterRespPdu) Tlvs() *TlvList {
+	return &p.Tlv
+}
+
+// GetHeader returns pdu header
+func (p *BindTransceiverPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *BindTransceiverPdu) CommandID() uint32 {
+	return BindTransceiver
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *BindTransceiverPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *BindTransceiverPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *BindTransceiverPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// GetHeader returns pdu header
+func (p *BindTransceiverRespPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *BindTransceiverRespPdu) CommandID() uint32 {
+	return BindTransceiverResp
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *BindTransceiverRespPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *BindTransceiverRespPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *BindTransceiverRespPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// Tlvs returns pdu optional parameters
+func (p *BindTransceiverRespPdu) Tlvs() *TlvList {
+	return &p.Tlv
+}
+
+// GetHeader returns pdu header
+func (p *SubmitSmPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *SubmitSmPdu) CommandID() uint32 {
+	return SubmitSm
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *SubmitSmPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *SubmitSmPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *SubmitSmPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// Tlvs returns pdu optional parameters
+func (p *SubmitSmPdu) Tlvs() *TlvList {
+	return &p.Tlv
+}
+
+// GetHeader returns pdu header
+func (p *SubmitSmRespPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *SubmitSmRespPdu) CommandID() uint32 {
+	return SubmitSmResp
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *SubmitSmRespPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *SubmitSmRespPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *SubmitSmRespPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// GetHeader returns pdu header
+func (p *DeliverSmPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *DeliverSmPdu) CommandID() uint32 {
+	return DeliverSm
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *DeliverSmPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *DeliverSmPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *DeliverSmPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// Tlvs returns pdu optional parameters
+func (p *DeliverSmPdu) Tlvs() *TlvList {
+	return &p.Tlv
+}
+
+// GetHeader returns pdu header
+func (p *DeliverSmRespPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *DeliverSmRespPdu) CommandID() uint32 {
+	return DeliverSmResp
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *DeliverSmRespPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *DeliverSmRespPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *DeliverSmRespPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// GetHeader returns pdu header
+func (p *QuerySmPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *QuerySmPdu) CommandID() uint32 {
+	return QuerySm
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *QuerySmPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *QuerySmPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *QuerySmPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// GetHeader returns pdu header
+func (p *QuerySmRespPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *QuerySmRespPdu) CommandID() uint32 {
+	return QuerySmResp
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *QuerySmRespPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *QuerySmRespPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *QuerySmRespPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// GetHeader returns pdu header
+func (p *ReplaceSmPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *ReplaceSmPdu) CommandID() uint32 {
+	return ReplaceSm
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *ReplaceSmPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *ReplaceSmPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *ReplaceSmPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// GetHeader returns pdu header
+func (p *ReplaceSmRespPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *ReplaceSmRespPdu) CommandID() uint32 {
+	return ReplaceSmResp
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *ReplaceSmRespPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *ReplaceSmRespPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *ReplaceSmRespPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// GetHeader returns pdu header
+func (p *CancelSmPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *CancelSmPdu) CommandID() uint32 {
+	return CancelSm
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *CancelSmPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *CancelSmPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *CancelSmPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// GetHeader returns pdu header
+func (p *CancelSmRespPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *CancelSmRespPdu) CommandID() uint32 {
+	return CancelSmResp
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *CancelSmRespPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *CancelSmRespPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *CancelSmRespPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// GetHeader returns pdu header
+func (p *SubmitMultiPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *SubmitMultiPdu) CommandID() uint32 {
+	return SubmitMulti
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *SubmitMultiPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *SubmitMultiPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *SubmitMultiPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// Tlvs returns pdu optional parameters
+func (p *SubmitMultiPdu) Tlvs() *TlvList {
+	return &p.Tlv
+}
+
+// GetHeader returns pdu header
+func (p *SubmitMultiRespPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *SubmitMultiRespPdu) CommandID() uint32 {
+	return SubmitMultiResp
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *SubmitMultiRespPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *SubmitMultiRespPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *SubmitMultiRespPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// GetHeader returns pdu header
+func (p *DataSmPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *DataSmPdu) CommandID() uint32 {
+	return DataSm
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *DataSmPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *DataSmPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *DataSmPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// Tlvs returns pdu optional parameters
+func (p *DataSmPdu) Tlvs() *TlvList {
+	return &p.Tlv
+}
+
+// GetHeader returns pdu header
+func (p *DataSmRespPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *DataSmRespPdu) CommandID() uint32 {
+	return DataSmResp
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *DataSmRespPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *DataSmRespPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *DataSmRespPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// Tlvs returns pdu optional parameters
+func (p *DataSmRespPdu) Tlvs() *TlvList {
+	return &p.Tlv
+}
+
+// GetHeader returns pdu header
+func (p *AlertNotificationPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *AlertNotificationPdu) CommandID() uint32 {
+	return AlertNotification
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *AlertNotificationPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *AlertNotificationPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *AlertNotificationPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// Tlvs returns pdu optional parameters
+func (p *AlertNotificationPdu) Tlvs() *TlvList {
+	return &p.Tlv
+}
+
+// GetHeader returns pdu header
+func (p *BroadcastSmPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *BroadcastSmPdu) CommandID() uint32 {
+	return BroadcastSm
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *BroadcastSmPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *BroadcastSmPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *BroadcastSmPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// Tlvs returns pdu optional parameters
+func (p *BroadcastSmPdu) Tlvs() *TlvList {
+	return &p.Tlv
+}
+
+// GetHeader returns pdu header
+func (p *BroadcastSmRespPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *BroadcastSmRespPdu) CommandID() uint32 {
+	return BroadcastSmResp
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *BroadcastSmRespPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *BroadcastSmRespPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *BroadcastSmRespPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// Tlvs returns pdu optional parameters
+func (p *BroadcastSmRespPdu) Tlvs() *TlvList {
+	return &p.Tlv
+}
+
+// GetHeader returns pdu header
+func (p *QueryBroadcastSmPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *QueryBroadcastSmPdu) CommandID() uint32 {
+	return QueryBroadcastSm
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *QueryBroadcastSmPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *QueryBroadcastSmPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *QueryBroadcastSmPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// Tlvs returns pdu optional parameters
+func (p *QueryBroadcastSmPdu) Tlvs() *TlvList {
+	return &p.Tlv
+}
+
+// GetHeader returns pdu header
+func (p *QueryBroadcastSmRespPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *QueryBroadcastSmRespPdu) CommandID() uint32 {
+	return QueryBroadcastSmResp
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *QueryBroadcastSmRespPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *QueryBroadcastSmRespPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *QueryBroadcastSmRespPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// Tlvs returns pdu optional parameters
+func (p *QueryBroadcastSmRespPdu) Tlvs() *TlvList {
+	return &p.Tlv
+}
+
+// GetHeader returns pdu header
+func (p *CancelBroadcastSmPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *CancelBroadcastSmPdu) CommandID() uint32 {
+	return CancelBroadcastSm
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *CancelBroadcastSmPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *CancelBroadcastSmPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *CancelBroadcastSmPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// Tlvs returns pdu optional parameters
+func (p *CancelBroadcastSmPdu) Tlvs() *TlvList {
+	return &p.Tlv
+}
+
+// GetHeader returns pdu header
+func (p *CancelBroadcastSmRespPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *CancelBroadcastSmRespPdu) CommandID() uint32 {
+	return CancelBroadcastSmResp
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *CancelBroadcastSmRespPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *CancelBroadcastSmRespPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *CancelBroadcastSmRespPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// GetHeader returns pdu header
+func (p *EnquireLinkPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *EnquireLinkPdu) CommandID() uint32 {
+	return EnquireLink
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *EnquireLinkPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *EnquireLinkPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *EnquireLinkPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// GetHeader returns pdu header
+func (p *EnquireLinkRespPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *EnquireLinkRespPdu) CommandID() uint32 {
+	return EnquireLinkResp
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *EnquireLinkRespPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *EnquireLinkRespPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *EnquireLinkRespPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// GetHeader returns pdu header
+func (p *GenericNackPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *GenericNackPdu) CommandID() uint32 {
+	return GenericNack
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *GenericNackPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *GenericNackPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *GenericNackPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// GetHeader returns pdu header
+func (p *UnbindPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *UnbindPdu) CommandID() uint32 {
+	return Unbind
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *UnbindPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *UnbindPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *UnbindPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// GetHeader returns pdu header
+func (p *UnbindRespPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *UnbindRespPdu) CommandID() uint32 {
+	return UnbindResp
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *UnbindRespPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *UnbindRespPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *UnbindRespPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
+
+// GetHeader returns pdu header
+func (p *OutBindPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *OutBindPdu) CommandID() uint32 {
+	return OutBind
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *OutBindPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *OutBindPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *OutBindPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
@@ -0,0 +1,61 @@
+package smpp
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestPdu_MarshalBinary(t *testing.T) {
+	for _, f := range encoderFixtures {
+		if f.pdu.CommandID() != f.pdu.GetHeader().CommandID {
+			t.Fatalf("%s: command id %x, want %x", f.name, f.pdu.CommandID(), f.pdu.GetHeader().CommandID)
+		}
+		data, err := f.pdu.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		if !bytes.Equal(data, f.wire) {
+			t.Fatalf("%s: marshalled % x, want % x", f.name, data, f.wire)
+		}
+		pdu := newPdu(f.pdu.CommandID())
+		if err := pdu.UnmarshalBinary(data); err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		if pdu.SequenceNumber() != f.pdu.SequenceNumber() {
+			t.Fatalf("%s: sequence number %d, want %d", f.name, pdu.SequenceNumber(), f.pdu.SequenceNumber())
+		}
+		again, err := pdu.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		if !bytes.Equal(again, f.wire) {
+			t.Fatalf("%s: round trip % x, want % x", f.name, again, f.wire)
+		}
+	}
+}
+
+func TestPdu_UnmarshalBinaryMismatch(t *testing.T) {
+	data, err := (&EnquireLinkPdu{Header: &Header{CommandID: EnquireLink, SequenceNumber: 1}}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := new(UnbindPdu).UnmarshalBinary(data); !errors.Is(err, ErrUnsupportedPdu) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestTlvPdu_Tlvs(t *testing.T) {
+	var pdu Pdu = &SubmitSmPdu{Tlv: TlvList{}}
+	p, ok := pdu.(TlvPdu)
+	if !ok {
+		t.Fatal("submit_sm does not carry tlvs")
+	}
+	p.Tlvs().Set(SarMsgRefNumTlv, []byte{0x00, 0x01})
+	if _, ok := pdu.(*SubmitSmPdu).Tlv.Get(SarMsgRefNumTlv); !ok {
+		t.Fatal("sar_msg_ref_num missing")
+	}
+	if _, ok := Pdu(&SubmitSmRespPdu{}).(TlvPdu); ok {
+		t.Fatal("submit_sm_resp carries tlvs")
+	}
+}
@@ -0,0 +1,100 @@
+package smpp
+
+import "errors"
+
+// ErrUnsupportedVersion throws when pdu does not exist in selected interface version
+var ErrUnsupportedVersion = errors.New("pdu unsupported by interface version")
+
+// Profile describes pdus and features available in smpp interface version
+type Profile struct {
+	Version uint32
+	Tlv     bool
+	pdus    map[uint32]bool
+}
+
+// Supports reports whether command id exists in profile interface version
+func (p *Profile) Supports(commandID uint32) bool {
+	return p.pdus[commandID]
+}
+
+// Allows reports whether pdu with command id may be used with profile,
+// command ids unknown to every version are left to Registry
+func (p *Profile) Allows(commandID uint32) bool {
+	return p.pdus[commandID] || !Profile50.pdus[commandID]
+}
+
+// SMPP v3.3 - 4 page 19
+var pdus33 = []uint32{
+	GenericNack,
+	BindReceiver,
+	BindReceiverResp,
+	BindTransmitter,
+	BindTransmitterResp,
+	QuerySm,
+	QuerySmResp,
+	SubmitSm,
+	SubmitSmResp,
+	DeliverSm,
+	DeliverSmResp,
+	Unbind,
+	UnbindResp,
+	ReplaceSm,
+	ReplaceSmResp,
+	CancelSm,
+	CancelSmResp,
+	EnquireLink,
+	EnquireLinkResp,
+	SubmitMulti,
+	SubmitMultiResp,
+}
+
+// SMPP v3.4 - 5.1.2.1 page 110-111
+var pdus34 = []uint32{
+	BindTransceiver,
+	BindTransceiverResp,
+	OutBind,
+	AlertNotification,
+	DataSm,
+	DataSmResp,
+}
+
+// SMPP v5.0 - 4.7.5 page 115-116
+var pdus50 = []uint32{
+	BroadcastSm,
+	BroadcastSmResp,
+	QueryBroadcastSm,
+	QueryBroadcastSmResp,
+	CancelBroadcastSm,
+	CancelBroadcastSmResp,
+}
+
+// newProfile constructs Profile from command id sets
+func newProfile(version uint32, tlv bool, sets ...[]uint32) *Profile {
+	p := &Profile{Version: version, Tlv: tlv, pdus: map[uint32]bool{}}
+	for _, set := range sets {
+		for _, id := range set {
+			p.pdus[id] = true
+		}
+	}
+	return p
+}
+
+// Profile33 is SMPP v3.3 profile, no tlvs and no bind_transceiver, outbind, data_sm or alert_notification
+var Profile33 = newProfile(InterfaceVersion33, false, pdus33)
+
+// Profile34 is SMPP v3.4 profile
+var Profile34 = newProfile(InterfaceVersion34, true, pdus33, pdus34)
+
+// Profile50 is SMPP v5.0 profile
+var Profile50 = newProfile(InterfaceVersion50, true, pdus33, pdus34, pdus50)
+
+// ProfileFor returns profile for interface version
+func ProfileFor(version uint32) *Profile {
+	switch NegotiateInterfaceVersion(version) {
+	case InterfaceVersion50:
+		return Profile50
+	case InterfaceVersion34:
+		return Profile34
+	}
+	return Profile33
+}
@@ -0,0 +1,118 @@
+package smpp
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestEncoder_EncodeVersion33(t *testing.T) {
+	bind := &BindTransmitterPdu{
+		Header: &Header{CommandID: BindTransmitter, SequenceNumber: 1},
+		Body:   &BindBody{SystemID: "test", Password: "pass", InterfaceVersion: InterfaceVersion33},
+	}
+	encoder := NewEncoder(new(bytes.Buffer))
+	if err := encoder.Encode(bind); err != nil {
+		t.Fatal(err)
+	}
+	if encoder.Version() != InterfaceVersion33 {
+		t.Fatalf("version %x", encoder.Version())
+	}
+	pdu := &SubmitSmPdu{
+		Header: &Header{CommandID: SubmitSm, SequenceNumber: 2},
+		Body:   &SmBody{SourceAddr: "sender", DestinationAddr: "79001234567", SmLength: 2, ShortMessage: []byte("hi")},
+		Tlv:    TlvList{},
+	}
+	plain := new(bytes.Buffer)
+	if err := NewEncoder(plain).Encode(pdu); err != nil {
+		t.Fatal(err)
+	}
+	pdu.Tlv.Set(UserMessageReferenceTlv, []byte{0x00, 0x01})
+	buffer := new(bytes.Buffer)
+	encoder = NewEncoder(buffer)
+	encoder.SetVersion(InterfaceVersion33)
+	if err := encoder.Encode(pdu); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buffer.Bytes(), plain.Bytes()) {
+		t.Fatalf("tlv encoded for v3.3: % x", buffer.Bytes())
+	}
+	transceiver := &BindTransceiverPdu{
+		Header: &Header{CommandID: BindTransceiver, SequenceNumber: 3},
+		Body:   &BindBody{SystemID: "test", Password: "pass", InterfaceVersion: InterfaceVersion33},
+	}
+	if err := NewEncoder(new(bytes.Buffer)).Encode(transceiver); err != ErrUnsupportedVersion {
+		t.Fatalf("unexpected error %v", err)
+	}
+	data := &DataSmPdu{
+		Header: &Header{CommandID: DataSm, SequenceNumber: 4},
+		Body:   &DataSmBody{},
+		Tlv:    TlvList{},
+	}
+	encoder = NewEncoder(new(bytes.Buffer))
+	encoder.SetVersion(InterfaceVersion33)
+	if err := encoder.Encode(data); err != ErrUnsupportedVersion {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestDecoder_DecodeVersion33(t *testing.T) {
+	buffer := bytes.NewBuffer(join(
+		header(24, SubmitSmResp, EsmeRok, 1), []byte("1a2b3c4d"),
+		header(16, SubmitSmResp, EsmeRsubmitFail, 2),
+		header(21, SubmitSmResp, EsmeRok, 3), []byte{'f', 'f', 0x00, 0x02, 0x04},
+	))
+	decoder := NewDecoder(buffer)
+	decoder.SetVersion(InterfaceVersion33)
+	for _, want := range []string{"1a2b3c4d", "", "ff"} {
+		rep, err := decoder.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pdu, ok := rep.(*SubmitSmRespPdu)
+		if !ok {
+			t.Fatal()
+		}
+		if pdu.Body.MessageID != want {
+			t.Fatalf("message id %q, want %q", pdu.Body.MessageID, want)
+		}
+	}
+	if buffer.Len() != 0 {
+		t.Fatalf("%d bytes left", buffer.Len())
+	}
+	buffer = bytes.NewBuffer(join(header(16, DataSmResp, EsmeRok, 4)))
+	decoder = NewDecoder(buffer)
+	decoder.SetVersion(InterfaceVersion33)
+	if _, err := decoder.Decode(); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestDecoder_BindRespVersion(t *testing.T) {
+	buffer := bytes.NewBuffer(join(
+		header(21, BindTransmitterResp, EsmeRok, 1), cstr("smsc"),
+		header(16, SubmitSmResp, EsmeRsubmitFail, 2),
+		header(26, BindTransmitterResp, EsmeRok, 3), cstr("smsc"), []byte{0x02, 0x10, 0x00, 0x01, 0x34},
+	))
+	decoder := NewDecoder(buffer)
+	for _, want := range []uint32{InterfaceVersion33, InterfaceVersion33, InterfaceVersion34} {
+		if _, err := decoder.Decode(); err != nil {
+			t.Fatal(err)
+		}
+		if decoder.Version() != want {
+			t.Fatalf("version %x, want %x", decoder.Version(), want)
+		}
+	}
+}
+
+func TestProfileFor(t *testing.T) {
+	if p := ProfileFor(0x00); p != Profile33 || p.Tlv || p.Supports(BindTransceiver) {
+		t.Fatalf("unexpected profile %+v", p)
+	}
+	if p := ProfileFor(InterfaceVersion34); p != Profile34 || !p.Supports(DataSm) || p.Supports(BroadcastSm) {
+		t.Fatalf("unexpected profile %+v", p)
+	}
+	if p := ProfileFor(InterfaceVersion50); p != Profile50 || !p.Supports(BroadcastSm) {
+		t.Fatalf("unexpected profile %+v", p)
+	}
+}
@@ -1,6 +1,9 @@
 package smpp
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // ErrUnsupportedPdu throws when pdu passed to Encode() is not a pointer to supported pdu structure
 var ErrUnsupportedPdu = errors.New("pdu unsupported or not a pointer")
@@ -48,6 +51,21 @@ const (
 	OutBind             uint32 = 0x0000000B
 	EnquireLink         uint32 = 0x00000015
 	EnquireLinkResp     uint32 = 0x80000015
+	SubmitMulti         uint32 = 0x00000021
+	SubmitMultiResp     uint32 = 0x80000021
+	AlertNotification   uint32 = 0x00000102
+	DataSm              uint32 = 0x00000103
+	DataSmResp          uint32 = 0x80000103
+)
+
+// Command ids - SMPP v5.0 - 4.7.5 page 115-116
+const (
+	BroadcastSm           uint32 = 0x00000111
+	BroadcastSmResp       uint32 = 0x80000111
+	QueryBroadcastSm      uint32 = 0x00000112
+	QueryBroadcastSmResp  uint32 = 0x80000112
+	CancelBroadcastSm     uint32 = 0x00000113
+	CancelBroadcastSmResp uint32 = 0x80000113
 )
 
 //  Command status - SMPP v3.4 - 5.1.3 page 112-114
@@ -269,6 +287,24 @@ const (
 // SMPP v3.4 - 5.2.13 page 123
 const ProtocolId uint32 = 0x34
 
+// Interface versions - SMPP v5.0 - 4.7.13 page 126
+const (
+	InterfaceVersion33 uint32 = 0x33
+	InterfaceVersion34 uint32 = 0x34
+	InterfaceVersion50 uint32 = 0x50
+)
+
+// NegotiateInterfaceVersion returns highest supported interface version not above requested one
+func NegotiateInterfaceVersion(requested uint32) uint32 {
+	switch {
+	case requested >= InterfaceVersion50:
+		return InterfaceVersion50
+	case requested >= InterfaceVersion34:
+		return InterfaceVersion34
+	}
+	return InterfaceVersion33
+}
+
 // SMPP v3.4 - 5.2.14 page 123
 const (
 	PriorityFlag0 uint32 = 0x00
@@ -328,6 +364,9 @@ const (
 	DestFlagDistlist uint32 = 2
 )
 
+// SMPP v3.4 - 4.5.1 page 71
+const MaxNumberOfDests uint32 = 255
+
 // SMPP v3.4 - 5.2.28 page 130
 const (
 	StateEnroute       uint32 = 1
@@ -388,6 +427,80 @@ const (
 	ItsSessionInfoTlv           uint32 = 0x1383
 )
 
+// SMPP v5.0 - 4.8.4 page 133-134
+const (
+	BroadcastChannelIndicatorTlv     uint32 = 0x0600
+	BroadcastContentTypeTlv          uint32 = 0x0601
+	BroadcastContentTypeInfoTlv      uint32 = 0x0602
+	BroadcastMessageClassTlv         uint32 = 0x0603
+	BroadcastRepNumTlv               uint32 = 0x0604
+	BroadcastFrequencyIntervalTlv    uint32 = 0x0605
+	BroadcastAreaIdentifierTlv       uint32 = 0x0606
+	BroadcastErrorStatusTlv          uint32 = 0x0607
+	BroadcastAreaSuccessTlv          uint32 = 0x0608
+	BroadcastEndTimeTlv              uint32 = 0x0609
+	BroadcastServiceGroupTlv         uint32 = 0x060A
+	FailedBroadcastAreaIdentifierTlv uint32 = 0x060B
+)
+
+// SMPP v5.0 - 4.8.4.4 page 136
+const (
+	BroadcastAreaFormatAlias        uint32 = 0x00
+	BroadcastAreaFormatEllipsoidArc uint32 = 0x01
+	BroadcastAreaFormatPolygon      uint32 = 0x02
+)
+
+// SMPP v5.0 - 4.8.4.6 page 137
+const (
+	BroadcastNetworkGeneric uint32 = 0x00
+	BroadcastNetworkGsm     uint32 = 0x01
+	BroadcastNetworkTdma    uint32 = 0x02
+	BroadcastNetworkCdma    uint32 = 0x03
+)
+
+// SMPP v5.0 - 4.8.4.9 page 139
+const (
+	BroadcastFrequencyAsap    uint32 = 0x00
+	BroadcastFrequencySeconds uint32 = 0x08
+	BroadcastFrequencyMinutes uint32 = 0x09
+	BroadcastFrequencyHours   uint32 = 0x0A
+	BroadcastFrequencyDays    uint32 = 0x0B
+	BroadcastFrequencyWeeks   uint32 = 0x0C
+	BroadcastFrequencyMonths  uint32 = 0x0D
+	BroadcastFrequencyYears   uint32 = 0x0E
+)
+
+// SMPP v5.0 - 4.8.4.7 page 138
+const BroadcastAreaSuccessUnknown uint32 = 255
+
+// SMPP v3.4 - 5.3.2.33 page 155
+const (
+	DeliveryFailureDestUnavailable     uint32 = 0
+	DeliveryFailureDestAddrInvalid     uint32 = 1
+	DeliveryFailurePermanentNetworkErr uint32 = 2
+	DeliveryFailureTemporaryNetworkErr uint32 = 3
+)
+
+// SMPP v3.4 - 5.3.2.31 page 154
+const (
+	NetworkTypeAnsi136 uint32 = 1
+	NetworkTypeIs95    uint32 = 2
+	NetworkTypeGsm     uint32 = 3
+)
+
+// SMPP v3.4 - 5.3.2.28 page 152
+const (
+	DpfNotSet uint32 = 0
+	DpfSet    uint32 = 1
+)
+
+// SMPP v3.4 - 5.3.2.30 page 153
+const (
+	MsAvailable   uint32 = 0
+	MsDenied      uint32 = 1
+	MsUnavailable uint32 = 2
+)
+
 var TlvNames = map[uint32]string{
 	DestAddrSubunitTlv:          "dest_addr_subunit",
 	DestNetworkTypeTlv:          "dest_network_type",
@@ -433,6 +546,20 @@ var TlvNames = map[uint32]string{
 	AlertOnMessageDeliveryTlv:   "alert_on_message_delivery",
 	ItsReplyTypeTlv:             "its_reply_type",
 	ItsSessionInfoTlv:           "its_session_info",
+
+	// SMPP v5.0
+	BroadcastChannelIndicatorTlv:     "broadcast_channel_indicator",
+	BroadcastContentTypeTlv:          "broadcast_content_type",
+	BroadcastContentTypeInfoTlv:      "broadcast_content_type_info",
+	BroadcastMessageClassTlv:         "broadcast_message_class",
+	BroadcastRepNumTlv:               "broadcast_rep_num",
+	BroadcastFrequencyIntervalTlv:    "broadcast_frequency_interval",
+	BroadcastAreaIdentifierTlv:       "broadcast_area_identifier",
+	BroadcastErrorStatusTlv:          "broadcast_error_status",
+	BroadcastAreaSuccessTlv:          "broadcast_area_success",
+	BroadcastEndTimeTlv:              "broadcast_end_time",
+	BroadcastServiceGroupTlv:         "broadcast_service_group",
+	FailedBroadcastAreaIdentifierTlv: "failed_broadcast_area_identifier",
 }
 
 // TlvName returns tlv tag name by tag id
@@ -442,3 +569,109 @@ func TlvName(tag uint32) string {
 	}
 	return "unknown"
 }
+
+// CommandNames maps command ids to spec names
+var CommandNames = map[uint32]string{
+	GenericNack:           "generic_nack",
+	BindReceiver:          "bind_receiver",
+	BindReceiverResp:      "bind_receiver_resp",
+	BindTransmitter:       "bind_transmitter",
+	BindTransmitterResp:   "bind_transmitter_resp",
+	QuerySm:               "query_sm",
+	QuerySmResp:           "query_sm_resp",
+	SubmitSm:              "submit_sm",
+	SubmitSmResp:          "submit_sm_resp",
+	DeliverSm:             "deliver_sm",
+	DeliverSmResp:         "deliver_sm_resp",
+	Unbind:                "unbind",
+	UnbindResp:            "unbind_resp",
+	ReplaceSm:             "replace_sm",
+	ReplaceSmResp:         "replace_sm_resp",
+	CancelSm:              "cancel_sm",
+	CancelSmResp:          "cancel_sm_resp",
+	BindTransceiver:       "bind_transceiver",
+	BindTransceiverResp:   "bind_transceiver_resp",
+	OutBind:               "outbind",
+	EnquireLink:           "enquire_link",
+	EnquireLinkResp:       "enquire_link_resp",
+	SubmitMulti:           "submit_multi",
+	SubmitMultiResp:       "submit_multi_resp",
+	AlertNotification:     "alert_notification",
+	DataSm:                "data_sm",
+	DataSmResp:            "data_sm_resp",
+	BroadcastSm:           "broadcast_sm",
+	BroadcastSmResp:       "broadcast_sm_resp",
+	QueryBroadcastSm:      "query_broadcast_sm",
+	QueryBroadcastSmResp:  "query_broadcast_sm_resp",
+	CancelBroadcastSm:     "cancel_broadcast_sm",
+	CancelBroadcastSmResp: "cancel_broadcast_sm_resp",
+}
+
+// CommandName returns command name by command id
+func CommandName(commandID uint32) string {
+	if name, ok := CommandNames[commandID]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%08X", commandID)
+}
+
+// StatusNames maps command status codes to spec names
+var StatusNames = map[uint32]string{
+	EsmeRok:              "ESME_ROK",
+	EsmeRinvMsgLen:       "ESME_RINVMSGLEN",
+	EsmeRinvCmdLen:       "ESME_RINVCMDLEN",
+	EsmeRinvCmdId:        "ESME_RINVCMDID",
+	EsmeRinvBndSts:       "ESME_RINVBNDSTS",
+	EsmeRalyBnd:          "ESME_RALYBND",
+	EsmeRinvPrtFlg:       "ESME_RINVPRTFLG",
+	EsmeRinvRegDlvFlg:    "ESME_RINVREGDLVFLG",
+	EsmeRsysErr:          "ESME_RSYSERR",
+	EsmeRinvSrcAdr:       "ESME_RINVSRCADR",
+	EsmeRinvDstAdr:       "ESME_RINVDSTADR",
+	EsmeRinvMsgId:        "ESME_RINVMSGID",
+	EsmeRbindFail:        "ESME_RBINDFAIL",
+	EsmeRinvPaswd:        "ESME_RINVPASWD",
+	EsmeRinvSysId:        "ESME_RINVSYSID",
+	EsmeRcancelFail:      "ESME_RCANCELFAIL",
+	EsmeRreplaceFail:     "ESME_RREPLACEFAIL",
+	EsmeRmsgqFul:         "ESME_RMSGQFUL",
+	EsmeRinvSerTyp:       "ESME_RINVSERTYP",
+	EsmeRinvNumDests:     "ESME_RINVNUMDESTS",
+	EsmeRinvDlName:       "ESME_RINVDLNAME",
+	EsmeRinvDestFlag:     "ESME_RINVDESTFLAG",
+	EsmeRinvSubRep:       "ESME_RINVSUBREP",
+	EsmeRinvEsmClass:     "ESME_RINVESMCLASS",
+	EsmeRcntSubDl:        "ESME_RCNTSUBDL",
+	EsmeRsubmitFail:      "ESME_RSUBMITFAIL",
+	EsmeRinvSrcTon:       "ESME_RINVSRCTON",
+	EsmeRinvSrcNpi:       "ESME_RINVSRCNPI",
+	EsmeRinvDstTon:       "ESME_RINVDSTTON",
+	EsmeRinvDstNpi:       "ESME_RINVDSTNPI",
+	EsmeRinvSysTyp:       "ESME_RINVSYSTYP",
+	EsmeRinvRepFlag:      "ESME_RINVREPFLAG",
+	EsmeRinvNumMsgs:      "ESME_RINVNUMMSGS",
+	EsmeRthrottled:       "ESME_RTHROTTLED",
+	EsmeRinvSched:        "ESME_RINVSCHED",
+	EsmeRinvExpiry:       "ESME_RINVEXPIRY",
+	EsmeRinvDftMsgId:     "ESME_RINVDFTMSGID",
+	EsmeRxTAppn:          "ESME_RXTAPPN",
+	EsmeRxPAppn:          "ESME_RXPAPPN",
+	EsmeRxRAppn:          "ESME_RXRAPPN",
+	EsmeRqueryFail:       "ESME_RQUERYFAIL",
+	EsmeRinvoptParStream: "ESME_RINVOPTPARSTREAM",
+	EsmeRoptParNotAllwd:  "ESME_ROPTPARNOTALLWD",
+	EsmeRinvParLen:       "ESME_RINVPARLEN",
+	EsmeRmissingOptParam: "ESME_RMISSINGOPTPARAM",
+	EsmeRinvOptParamVal:  "ESME_RINVOPTPARAMVAL",
+	EsmeRdeliveryFailure: "ESME_RDELIVERYFAILURE",
+	EsmeRinvDcs:          "ESME_RINVDCS",
+	EsmeRunknownErr:      "ESME_RUNKNOWNERR",
+}
+
+// StatusName returns command status name by code
+func StatusName(code uint32) string {
+	if name, ok := StatusNames[code]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%08X", code)
+}
@@ -1,41 +1,90 @@
 package smpp
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/binary"
+	"fmt"
 	"io"
-	"net"
 )
 
+// DefaultMaxPduSize is default maximal command_length accepted by Reader
+const DefaultMaxPduSize = 64 * 1024
+
+// FrameError reports pdu with command_length out of bounds, the pdu body
+// is not read so the stream should be closed after answering generic_nack
+type FrameError struct {
+	CommandLength  uint32
+	CommandID      uint32
+	SequenceNumber uint32
+}
+
+// Error implements error
+func (e *FrameError) Error() string {
+	return fmt.Sprintf("command length %d is invalid, command id 0x%08X, sequence number %d",
+		e.CommandLength, e.CommandID, e.SequenceNumber)
+}
+
+// Unwrap returns ErrEsmeRinvCmdLen
+func (e *FrameError) Unwrap() error {
+	return ErrEsmeRinvCmdLen
+}
+
+// Status returns command status to answer generic_nack with
+func (e *FrameError) Status() uint32 {
+	return EsmeRinvCmdLen
+}
+
 // Reader reads pdu buffer
 type Reader struct {
-	conn *net.TCPConn
+	r          io.Reader
+	maxPduSize uint32
+	header     [PduHeaderLength]byte
+	// body is reused between pdus
+	body []byte
+}
+
+// NewReader reader constructor, reads pdus straight from r
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r, maxPduSize: DefaultMaxPduSize}
+}
+
+// NewReaderSize buffered reader constructor
+func NewReaderSize(r io.Reader, size int) *Reader {
+	return NewReader(bufio.NewReaderSize(r, size))
 }
 
-// NewReader reader constructor
-func NewReader(conn *net.TCPConn) *Reader {
-	return &Reader{conn: conn}
+// SetMaxPduSize sets maximal command_length accepted
+func (r *Reader) SetMaxPduSize(size uint32) {
+	r.maxPduSize = size
 }
 
 // Reader read pdu to buffer
 func (r *Reader) Read(buffer *bytes.Buffer) error {
-	p := make([]byte, 4)
-	n, err := io.ReadFull(r.conn, p)
-	if err != nil {
+	p := r.header[:]
+	if _, err := io.ReadFull(r.r, p); err != nil {
 		return err
 	}
-	if n < len(p) {
+	commandLength := binary.BigEndian.Uint32(p)
+	if commandLength < PduHeaderLength || commandLength > r.maxPduSize {
+		return &FrameError{
+			CommandLength:  commandLength,
+			CommandID:      binary.BigEndian.Uint32(p[4:]),
+			SequenceNumber: binary.BigEndian.Uint32(p[12:]),
+		}
+	}
+	if uint32(cap(r.body)) < commandLength-PduHeaderLength {
+		r.body = make([]byte, commandLength-PduHeaderLength)
+	}
+	b := r.body[:commandLength-PduHeaderLength]
+	_, err := io.ReadFull(r.r, b)
+	if err == io.EOF {
 		return io.ErrUnexpectedEOF
 	}
-	commandLength := int32(p[3]) | int32(p[2])<<8 | int32(p[1])<<16 | int32(p[0])<<24
-	b := make([]byte, commandLength-4)
-	n, err = io.ReadFull(r.conn, b)
 	if err != nil {
 		return err
 	}
-	if n < len(b) {
-		return io.ErrUnexpectedEOF
-	}
-	n, err = buffer.Write(p)
+	n, err := buffer.Write(p)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,42 @@
+package smpp
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestReader_ReadFrameError(t *testing.T) {
+	tests := []struct {
+		name string
+		wire []byte
+		max  uint32
+	}{
+		{name: "undersized", wire: header(4, SubmitSm, 0, 7), max: DefaultMaxPduSize},
+		{name: "oversized", wire: header(0xFFFFFFFF, SubmitSm, 0, 7), max: DefaultMaxPduSize},
+		{name: "over max", wire: header(17, SubmitSm, 0, 7), max: 16},
+	}
+	for _, test := range tests {
+		r := NewReader(bytes.NewReader(test.wire))
+		r.SetMaxPduSize(test.max)
+		err := r.Read(new(bytes.Buffer))
+		var frameErr *FrameError
+		if !errors.As(err, &frameErr) {
+			t.Fatalf("%s: unexpected error %v", test.name, err)
+		}
+		if frameErr.SequenceNumber != 7 || frameErr.CommandID != SubmitSm {
+			t.Fatalf("%s: unexpected frame error %+v", test.name, frameErr)
+		}
+		if frameErr.Status() != EsmeRinvCmdLen || !errors.Is(err, ErrEsmeRinvCmdLen) {
+			t.Fatalf("%s: unexpected status %v", test.name, err)
+		}
+	}
+}
+
+func TestDecoder_DecodeFrameError(t *testing.T) {
+	_, err := NewDecoder(bytes.NewBuffer(header(8, EnquireLink, 0, 3))).Decode()
+	var frameErr *FrameError
+	if !errors.As(err, &frameErr) || frameErr.SequenceNumber != 3 {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package smpp
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrPduRegistered throws when command id is already taken by builtin or registered pdu
+var ErrPduRegistered = errors.New("command id already registered")
+
+// PduFactory constructs empty custom pdu
+type PduFactory func() Pdu
+
+// PduCodec encodes and decodes custom pdu body
+type PduCodec interface {
+	// EncodeBody returns body octets of pdu, header is written by Encoder
+	EncodeBody(pdu Pdu) ([]byte, error)
+	// DecodeBody fills pdu constructed by factory with header and body octets
+	DecodeBody(pdu Pdu, header *Header, body []byte) error
+}
+
+type registryEntry struct {
+	factory PduFactory
+	codec   PduCodec
+}
+
+// Registry maps vendor specific command ids to custom pdu factories and codecs
+type Registry struct {
+	mu      sync.RWMutex
+	entries map[uint32]registryEntry
+}
+
+// NewRegistry constructs Registry
+func NewRegistry() *Registry {
+	return &Registry{entries: map[uint32]registryEntry{}}
+}
+
+// DefaultRegistry is used by encoders and decoders unless replaced with SetRegistry
+var DefaultRegistry = NewRegistry()
+
+// Register binds command id to custom pdu factory and codec
+func (r *Registry) Register(commandID uint32, factory PduFactory, codec PduCodec) error {
+	if newPdu(commandID) != nil {
+		return ErrPduRegistered
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[commandID]; ok {
+		return ErrPduRegistered
+	}
+	r.entries[commandID] = registryEntry{factory: factory, codec: codec}
+	return nil
+}
+
+// Unregister removes command id from registry
+func (r *Registry) Unregister(commandID uint32) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, commandID)
+}
+
+// lookup returns registry entry by command id
+func (r *Registry) lookup(commandID uint32) (registryEntry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.entries[commandID]
+	return entry, ok
+}
+
+// RegisterPdu binds command id to custom pdu factory and codec in DefaultRegistry
+func RegisterPdu(commandID uint32, factory PduFactory, codec PduCodec) error {
+	return DefaultRegistry.Register(commandID, factory, codec)
+}
+
+// RawPdu keeps header and body octets of pdu with unknown command id
+type RawPdu struct {
+	Header *Header
+	Body   []byte
+}
+
+// GetHeader returns pdu header
+func (p *RawPdu) GetHeader() *Header {
+	return p.Header
+}
+
+// CommandID returns pdu command id
+func (p *RawPdu) CommandID() uint32 {
+	if p.Header == nil {
+		return 0
+	}
+	return p.Header.CommandID
+}
+
+// SequenceNumber returns pdu sequence number
+func (p *RawPdu) SequenceNumber() uint32 {
+	return sequenceNumber(p.Header)
+}
+
+// MarshalBinary encodes pdu
+func (p *RawPdu) MarshalBinary() ([]byte, error) {
+	return marshal(p)
+}
+
+// UnmarshalBinary decodes pdu
+func (p *RawPdu) UnmarshalBinary(data []byte) error {
+	return unmarshal(p, data)
+}
@@ -0,0 +1,100 @@
+package smpp
+
+import (
+	"bytes"
+	"testing"
+)
+
+const vendorPing uint32 = 0x00010200
+
+type vendorPingPdu struct {
+	Header  *Header
+	Payload string
+}
+
+func (p *vendorPingPdu) GetHeader() *Header                { return p.Header }
+func (p *vendorPingPdu) CommandID() uint32                 { return vendorPing }
+func (p *vendorPingPdu) SequenceNumber() uint32            { return p.Header.SequenceNumber }
+func (p *vendorPingPdu) MarshalBinary() ([]byte, error)    { return nil, nil }
+func (p *vendorPingPdu) UnmarshalBinary(data []byte) error { return nil }
+
+type vendorPingCodec struct{}
+
+func (vendorPingCodec) EncodeBody(pdu Pdu) ([]byte, error) {
+	return []byte(pdu.(*vendorPingPdu).Payload), nil
+}
+
+func (vendorPingCodec) DecodeBody(pdu Pdu, header *Header, body []byte) error {
+	p := pdu.(*vendorPingPdu)
+	p.Header = header
+	p.Payload = string(body)
+	return nil
+}
+
+func TestRegistry_Register(t *testing.T) {
+	registry := NewRegistry()
+	factory := func() Pdu { return &vendorPingPdu{} }
+	if err := registry.Register(SubmitSm, factory, vendorPingCodec{}); err != ErrPduRegistered {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := registry.Register(vendorPing, factory, vendorPingCodec{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register(vendorPing, factory, vendorPingCodec{}); err != ErrPduRegistered {
+		t.Fatalf("unexpected error %v", err)
+	}
+	req := &vendorPingPdu{Header: &Header{CommandID: vendorPing, SequenceNumber: 7}, Payload: "ping"}
+	buffer := new(bytes.Buffer)
+	encoder := NewEncoder(buffer)
+	encoder.SetRegistry(registry)
+	if err := encoder.Encode(req); err != nil {
+		t.Fatal(err)
+	}
+	wire := join(header(20, vendorPing, 0, 7), []byte("ping"))
+	if !bytes.Equal(buffer.Bytes(), wire) {
+		t.Fatalf("encoded % x, want % x", buffer.Bytes(), wire)
+	}
+	decoder := NewDecoder(buffer)
+	decoder.SetRegistry(registry)
+	rep, err := decoder.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdu, ok := rep.(*vendorPingPdu)
+	if !ok {
+		t.Fatalf("unexpected pdu %T", rep)
+	}
+	if pdu.Payload != "ping" || pdu.SequenceNumber() != 7 {
+		t.Fatalf("unexpected pdu %+v", pdu)
+	}
+	if err := NewEncoder(new(bytes.Buffer)).Encode(req); err != ErrUnsupportedPdu {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestDecoder_DecodeRaw(t *testing.T) {
+	wire := join(header(19, 0x00010201, 0, 3), []byte{0x01, 0x00, 0x02}, header(16, EnquireLink, 0, 4))
+	buffer := bytes.NewBuffer(wire)
+	decoder := NewDecoder(buffer)
+	rep, err := decoder.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, ok := rep.(*RawPdu)
+	if !ok {
+		t.Fatalf("unexpected pdu %T", rep)
+	}
+	if raw.CommandID() != 0x00010201 || !bytes.Equal(raw.Body, []byte{0x01, 0x00, 0x02}) {
+		t.Fatalf("unexpected pdu %+v", raw)
+	}
+	if _, err := decoder.Decode(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := raw.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, wire[:19]) {
+		t.Fatalf("round trip % x, want % x", data, wire[:19])
+	}
+}
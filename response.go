@@ -0,0 +1,186 @@
+package smpp
+
+// respHeader returns response header matching request sequence number
+func respHeader(request Pdu, commandID uint32, status uint32) *Header {
+	return &Header{
+		CommandID:      commandID,
+		CommandStatus:  status,
+		SequenceNumber: request.SequenceNumber(),
+	}
+}
+
+// NewGenericNack returns generic_nack answering header with status,
+// sequence number is zero when header is nil as it could not be decoded
+func NewGenericNack(header *Header, status uint32) *GenericNackPdu {
+	return &GenericNackPdu{
+		Header: &Header{
+			CommandID:      GenericNack,
+			CommandStatus:  status,
+			SequenceNumber: sequenceNumber(header),
+		},
+	}
+}
+
+// Response returns response answering request pdu with status,
+// generic_nack answers unknown command ids, nil is returned for
+// responses, alert_notification and outbind which are never answered
+func Response(pdu Pdu, status uint32) Pdu {
+	switch p := pdu.(type) {
+	case *BindReceiverPdu:
+		return p.Response(status)
+	case *BindTransmitterPdu:
+		return p.Response(status)
+	case *BindTransceiverPdu:
+		return p.Response(status)
+	case *SubmitSmPdu:
+		return p.Response(status)
+	case *DeliverSmPdu:
+		return p.Response(status)
+	case *QuerySmPdu:
+		return p.Response(status)
+	case *ReplaceSmPdu:
+		return p.Response(status)
+	case *CancelSmPdu:
+		return p.Response(status)
+	case *SubmitMultiPdu:
+		return p.Response(status)
+	case *DataSmPdu:
+		return p.Response(status)
+	case *BroadcastSmPdu:
+		return p.Response(status)
+	case *QueryBroadcastSmPdu:
+		return p.Response(status)
+	case *CancelBroadcastSmPdu:
+		return p.Response(status)
+	case *EnquireLinkPdu:
+		return p.Response(status)
+	case *UnbindPdu:
+		return p.Response(status)
+	}
+	id := pdu.CommandID()
+	if _, ok := CommandNames[id]; ok || id&GenericNack != 0 {
+		return nil
+	}
+	return NewGenericNack(pdu.GetHeader(), status)
+}
+
+// Response returns bind_receiver_resp answering pdu with status
+func (p *BindReceiverPdu) Response(status uint32) *BindReceiverRespPdu {
+	return &BindReceiverRespPdu{
+		Header: respHeader(p, BindReceiverResp, status),
+		Body:   &BindRespBody{},
+		Tlv:    TlvList{},
+	}
+}
+
+// Response returns bind_transmitter_resp answering pdu with status
+func (p *BindTransmitterPdu) Response(status uint32) *BindTransmitterRespPdu {
+	return &BindTransmitterRespPdu{
+		Header: respHeader(p, BindTransmitterResp, status),
+		Body:   &BindRespBody{},
+		Tlv:    TlvList{},
+	}
+}
+
+// Response returns bind_transceiver_resp answering pdu with status
+func (p *BindTransceiverPdu) Response(status uint32) *BindTransceiverRespPdu {
+	return &BindTransceiverRespPdu{
+		Header: respHeader(p, BindTransceiverResp, status),
+		Body:   &BindRespBody{},
+		Tlv:    TlvList{},
+	}
+}
+
+// Response returns submit_sm_resp answering pdu with status
+func (p *SubmitSmPdu) Response(status uint32) *SubmitSmRespPdu {
+	return &SubmitSmRespPdu{
+		Header: respHeader(p, SubmitSmResp, status),
+		Body:   &SmRespBody{},
+	}
+}
+
+// Response returns deliver_sm_resp answering pdu with status
+func (p *DeliverSmPdu) Response(status uint32) *DeliverSmRespPdu {
+	return &DeliverSmRespPdu{
+		Header: respHeader(p, DeliverSmResp, status),
+		Body:   &SmRespBody{},
+	}
+}
+
+// Response returns query_sm_resp answering pdu with status
+func (p *QuerySmPdu) Response(status uint32) *QuerySmRespPdu {
+	return &QuerySmRespPdu{
+		Header: respHeader(p, QuerySmResp, status),
+		Body:   &QuerySmRespBody{},
+	}
+}
+
+// Response returns replace_sm_resp answering pdu with status
+func (p *ReplaceSmPdu) Response(status uint32) *ReplaceSmRespPdu {
+	return &ReplaceSmRespPdu{
+		Header: respHeader(p, ReplaceSmResp, status),
+	}
+}
+
+// Response returns cancel_sm_resp answering pdu with status
+func (p *CancelSmPdu) Response(status uint32) *CancelSmRespPdu {
+	return &CancelSmRespPdu{
+		Header: respHeader(p, CancelSmResp, status),
+	}
+}
+
+// Response returns submit_multi_resp answering pdu with status
+func (p *SubmitMultiPdu) Response(status uint32) *SubmitMultiRespPdu {
+	return &SubmitMultiRespPdu{
+		Header: respHeader(p, SubmitMultiResp, status),
+		Body:   &SubmitMultiRespBody{},
+	}
+}
+
+// Response returns data_sm_resp answering pdu with status
+func (p *DataSmPdu) Response(status uint32) *DataSmRespPdu {
+	return &DataSmRespPdu{
+		Header: respHeader(p, DataSmResp, status),
+		Body:   &SmRespBody{},
+		Tlv:    TlvList{},
+	}
+}
+
+// Response returns broadcast_sm_resp answering pdu with status
+func (p *BroadcastSmPdu) Response(status uint32) *BroadcastSmRespPdu {
+	return &BroadcastSmRespPdu{
+		Header: respHeader(p, BroadcastSmResp, status),
+		Body:   &SmRespBody{},
+		Tlv:    TlvList{},
+	}
+}
+
+// Response returns query_broadcast_sm_resp answering pdu with status
+func (p *QueryBroadcastSmPdu) Response(status uint32) *QueryBroadcastSmRespPdu {
+	return &QueryBroadcastSmRespPdu{
+		Header: respHeader(p, QueryBroadcastSmResp, status),
+		Body:   &SmRespBody{},
+		Tlv:    TlvList{},
+	}
+}
+
+// Response returns cancel_broadcast_sm_resp answering pdu with status
+func (p *CancelBroadcastSmPdu) Response(status uint32) *CancelBroadcastSmRespPdu {
+	return &CancelBroadcastSmRespPdu{
+		Header: respHeader(p, CancelBroadcastSmResp, status),
+	}
+}
+
+// Response returns enquire_link_resp answering pdu with status
+func (p *EnquireLinkPdu) Response(status uint32) *EnquireLinkRespPdu {
+	return &EnquireLinkRespPdu{
+		Header: respHeader(p, EnquireLinkResp, status),
+	}
+}
+
+// Response returns unbind_resp answering pdu with status
+func (p *UnbindPdu) Response(status uint32) *UnbindRespPdu {
+	return &UnbindRespPdu{
+		Header: respHeader(p, UnbindResp, status),
+	}
+}
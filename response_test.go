@@ -0,0 +1,52 @@
+package smpp
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestResponse(t *testing.T) {
+	resp := (&SubmitSmPdu{Header: &Header{CommandID: SubmitSm, SequenceNumber: 42}}).Response(EsmeRthrottled)
+	if resp.CommandID() != SubmitSmResp || resp.SequenceNumber() != 42 || resp.Header.CommandStatus != EsmeRthrottled {
+		t.Fatalf("unexpected response %+v", resp.Header)
+	}
+	for id, name := range CommandNames {
+		if id&GenericNack != 0 {
+			continue
+		}
+		req, err := DecodeJSON([]byte(fmt.Sprintf(`{"command_id":%q,"command_status":"ESME_ROK","sequence_number":7}`, name)))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		resp := Response(req, EsmeRsysErr)
+		want := id | GenericNack
+		if newPdu(want) == nil {
+			if resp != nil {
+				t.Fatalf("%s: unexpected response %s", CommandName(id), resp)
+			}
+			continue
+		}
+		if resp.CommandID() != want || resp.SequenceNumber() != 7 || resp.GetHeader().CommandStatus != EsmeRsysErr {
+			t.Fatalf("%s: unexpected response %s", CommandName(id), resp)
+		}
+		if _, err := resp.MarshalBinary(); err != nil {
+			t.Fatalf("%s: %v", CommandName(id), err)
+		}
+	}
+	for _, id := range []uint32{SubmitSmResp, GenericNack, AlertNotification, OutBind} {
+		req, err := DecodeJSON([]byte(fmt.Sprintf(`{"command_id":%q,"command_status":"ESME_ROK","sequence_number":7}`, CommandName(id))))
+		if err != nil {
+			t.Fatalf("%s: %v", CommandName(id), err)
+		}
+		if resp := Response(req, EsmeRok); resp != nil {
+			t.Fatalf("%s: unexpected response %s", CommandName(id), resp)
+		}
+	}
+	unknown := &RawPdu{Header: &Header{CommandID: 0x00010200, SequenceNumber: 7}}
+	if nack := Response(unknown, EsmeRinvCmdId); nack == nil || nack.CommandID() != GenericNack || nack.SequenceNumber() != 7 {
+		t.Fatalf("unexpected response %v", nack)
+	}
+	if nack := NewGenericNack(nil, EsmeRinvCmdLen); nack.SequenceNumber() != 0 || nack.Header.CommandStatus != EsmeRinvCmdLen {
+		t.Fatalf("unexpected generic_nack %+v", nack.Header)
+	}
+}
@@ -0,0 +1,83 @@
+package smpp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// StatusError is pdu decoding error carrying command status to answer with
+type StatusError struct {
+	Code           uint32
+	CommandID      uint32
+	SequenceNumber uint32
+	Field          string
+	Offset         uint32
+	Err            error
+}
+
+// Error implements error
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("command id 0x%08X, sequence number %d, status 0x%08X: %v",
+		e.CommandID, e.SequenceNumber, e.Code, e.Err)
+}
+
+// Unwrap returns underlying error
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the ErrCodes error of status code
+func (e *StatusError) Is(target error) bool {
+	err, ok := ErrCodes[e.Code]
+	return ok && err == target
+}
+
+// Status returns command status for err, EsmeRok for nil error and
+// EsmeRunknownErr for errors not mapped to any status
+func Status(err error) uint32 {
+	if err == nil {
+		return EsmeRok
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code
+	}
+	var validationErrs ValidationErrors
+	if errors.As(err, &validationErrs) && len(validationErrs) > 0 {
+		return Status(validationErrs[0].Err)
+	}
+	var frameErr *FrameError
+	if errors.As(err, &frameErr) {
+		return frameErr.Status()
+	}
+	for code, codeErr := range ErrCodes {
+		if errors.Is(err, codeErr) {
+			return code
+		}
+	}
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return EsmeRinvCmdLen
+	case errors.Is(err, ErrUnsupportedPdu), errors.Is(err, ErrUnsupportedVersion):
+		return EsmeRinvCmdId
+	case errors.Is(err, ErrTlvKind):
+		return EsmeRinvOptParamVal
+	}
+	return EsmeRunknownErr
+}
+
+// statusError wraps err of pdu with header into StatusError
+func statusError(header *Header, err error) *StatusError {
+	e := &StatusError{Code: Status(err), Err: err}
+	if header != nil {
+		e.CommandID = header.CommandID
+		e.SequenceNumber = header.SequenceNumber
+	}
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		e.Field = fieldErr.Field
+		e.Offset = fieldErr.Offset
+	}
+	return e
+}
@@ -0,0 +1,49 @@
+package smpp
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestStatusError_Decode(t *testing.T) {
+	wire := join(
+		header(61, SubmitSm, 0, 9), cstr(""), []byte{0x05, 0x00}, cstr("averyveryverylongsender"),
+		[]byte{0x01, 0x01}, cstr("79001234567"), make([]byte, 10),
+	)
+	_, err := NewDecoder(bytes.NewBuffer(wire)).Decode()
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if statusErr.Code != EsmeRinvSrcAdr || statusErr.CommandID != SubmitSm || statusErr.SequenceNumber != 9 {
+		t.Fatalf("unexpected status error %+v", statusErr)
+	}
+	if statusErr.Field != "source_addr" || statusErr.Offset != 19 {
+		t.Fatalf("unexpected field %s at %d", statusErr.Field, statusErr.Offset)
+	}
+	if !errors.Is(err, ErrEsmeRinvSrcAdr) || !errors.Is(err, Err(statusErr.Code)) || Status(err) != EsmeRinvSrcAdr {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	for code, err := range ErrCodes {
+		if Status(err) != code {
+			t.Fatalf("status of %v is 0x%08X, want 0x%08X", err, Status(err), code)
+		}
+		statusErr := &StatusError{Code: code, Err: errors.New("test")}
+		if !errors.Is(statusErr, err) || Status(statusErr) != code {
+			t.Fatalf("status error 0x%08X does not match %v", code, err)
+		}
+	}
+	if Status(nil) != EsmeRok {
+		t.Fatal("nil error is not EsmeRok")
+	}
+	if Status(&FrameError{CommandLength: 1}) != EsmeRinvCmdLen {
+		t.Fatal("frame error is not EsmeRinvCmdLen")
+	}
+	if Status(errors.New("test")) != EsmeRunknownErr {
+		t.Fatal("unmapped error is not EsmeRunknownErr")
+	}
+}
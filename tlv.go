@@ -0,0 +1,479 @@
+package smpp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// ErrTlvKind throws when tlv value is accessed as a kind it is not defined with
+var ErrTlvKind = errors.New("tlv value kind mismatch")
+
+// TlvKind is a tlv value type
+type TlvKind int
+
+const (
+	TlvOctets TlvKind = iota
+	TlvInt8
+	TlvInt16
+	TlvInt32
+	TlvCString
+	TlvComposite
+)
+
+// TlvDef describes tlv value kind and allowed value length
+type TlvDef struct {
+	Kind   TlvKind
+	MinLen uint32
+	MaxLen uint32
+}
+
+// SMPP v3.4 - 5.3.2 page 134-159, SMPP v5.0 - 4.8.4 page 133-164
+var TlvDefs = map[uint32]TlvDef{
+	DestAddrSubunitTlv:          {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	DestNetworkTypeTlv:          {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	DestBearerTypeTlv:           {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	DestTelematicsIdTlv:         {Kind: TlvInt16, MinLen: 2, MaxLen: 2},
+	SourceAddrSubunitTlv:        {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	SourceNetworkTypeTlv:        {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	SourceBearerTypeTlv:         {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	SourceTelematicsIdTlv:       {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	QosTimeToLiveTlv:            {Kind: TlvInt32, MinLen: 4, MaxLen: 4},
+	PayloadTypeTlv:              {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	AdditionalStatusInfoTextTlv: {Kind: TlvCString, MinLen: 1, MaxLen: 256},
+	ReceiptedMessageIdTlv:       {Kind: TlvCString, MinLen: 1, MaxLen: 65},
+	MsMsgWaitFacilitiesTlv:      {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	PrivacyIndicatorTlv:         {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	SourceSubaddressTlv:         {Kind: TlvOctets, MinLen: 2, MaxLen: 23},
+	DestSubaddressTlv:           {Kind: TlvOctets, MinLen: 2, MaxLen: 23},
+	UserMessageReferenceTlv:     {Kind: TlvInt16, MinLen: 2, MaxLen: 2},
+	UserResponseCodeTlv:         {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	SourcePortTlv:               {Kind: TlvInt16, MinLen: 2, MaxLen: 2},
+	DestinationPortTlv:          {Kind: TlvInt16, MinLen: 2, MaxLen: 2},
+	SarMsgRefNumTlv:             {Kind: TlvInt16, MinLen: 2, MaxLen: 2},
+	LanguageIndicatorTlv:        {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	SarTotalSegmentsTlv:         {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	SarSegmentSeqnumTlv:         {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	ScInterfaceVersionTlv:       {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	CallbackNumPresIndTlv:       {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	CallbackNumAtagTlv:          {Kind: TlvOctets, MinLen: 0, MaxLen: 65},
+	NumberOfMessagesTlv:         {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	CallbackNumTlv:              {Kind: TlvComposite, MinLen: 4, MaxLen: 19},
+	DpfResultTlv:                {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	SetDpfTlv:                   {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	MsAvailabilityStatusTlv:     {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	NetworkErrorCodeTlv:         {Kind: TlvComposite, MinLen: 3, MaxLen: 3},
+	MessagePayloadTlv:           {Kind: TlvOctets, MinLen: 0, MaxLen: 65535},
+	DeliveryFailureReasonTlv:    {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	MoreMessagesToSendTlv:       {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	MessageStateTlv:             {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	UssdServiceOpTlv:            {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	DisplayTimeTlv:              {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	SmsSignalTlv:                {Kind: TlvInt16, MinLen: 2, MaxLen: 2},
+	MsValidityTlv:               {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	AlertOnMessageDeliveryTlv:   {Kind: TlvOctets, MinLen: 0, MaxLen: 1},
+	ItsReplyTypeTlv:             {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	ItsSessionInfoTlv:           {Kind: TlvComposite, MinLen: 2, MaxLen: 2},
+
+	// SMPP v5.0
+	BroadcastChannelIndicatorTlv:     {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	BroadcastContentTypeTlv:          {Kind: TlvComposite, MinLen: 3, MaxLen: 3},
+	BroadcastContentTypeInfoTlv:      {Kind: TlvOctets, MinLen: 0, MaxLen: 255},
+	BroadcastMessageClassTlv:         {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	BroadcastRepNumTlv:               {Kind: TlvInt16, MinLen: 2, MaxLen: 2},
+	BroadcastFrequencyIntervalTlv:    {Kind: TlvComposite, MinLen: 3, MaxLen: 3},
+	BroadcastAreaIdentifierTlv:       {Kind: TlvComposite, MinLen: 1, MaxLen: 101},
+	BroadcastErrorStatusTlv:          {Kind: TlvInt32, MinLen: 4, MaxLen: 4},
+	BroadcastAreaSuccessTlv:          {Kind: TlvInt8, MinLen: 1, MaxLen: 1},
+	BroadcastEndTimeTlv:              {Kind: TlvCString, MinLen: 1, MaxLen: 17},
+	BroadcastServiceGroupTlv:         {Kind: TlvOctets, MinLen: 0, MaxLen: 255},
+	FailedBroadcastAreaIdentifierTlv: {Kind: TlvComposite, MinLen: 1, MaxLen: 101},
+}
+
+// validate checks tlv value against its definition, unknown tags are accepted as octets
+func (tlv *Tlv) validate() error {
+	def, ok := TlvDefs[tlv.Tag]
+	if !ok {
+		return nil
+	}
+	l := uint32(len(tlv.Value))
+	if l < def.MinLen || l > def.MaxLen {
+		return ErrEsmeRinvParLen
+	}
+	if def.Kind == TlvCString && bytes.IndexByte(tlv.Value, 0) != len(tlv.Value)-1 {
+		return ErrEsmeRinvOptParamVal
+	}
+	return nil
+}
+
+// NetworkErrorCode is a network_error_code tlv value
+// SMPP v3.4 - 5.3.2.31 page 154
+type NetworkErrorCode struct {
+	NetworkType uint32
+	ErrorCode   uint32
+}
+
+// BroadcastAreaIdentifier is a broadcast_area_identifier tlv value
+// SMPP v5.0 - 4.8.4.4 page 136
+type BroadcastAreaIdentifier struct {
+	Format  uint32
+	Details []byte
+}
+
+// BroadcastContentType is a broadcast_content_type tlv value
+// SMPP v5.0 - 4.8.4.6 page 137
+type BroadcastContentType struct {
+	NetworkType uint32
+	ServiceType uint32
+}
+
+// BroadcastFrequencyInterval is a broadcast_frequency_interval tlv value
+// SMPP v5.0 - 4.8.4.9 page 139
+type BroadcastFrequencyInterval struct {
+	Unit  uint32
+	Value uint32
+}
+
+// broadcastSmTlvs are tlvs mandatory for broadcast_sm
+var broadcastSmTlvs = []uint32{
+	BroadcastAreaIdentifierTlv,
+	BroadcastContentTypeTlv,
+	BroadcastRepNumTlv,
+	BroadcastFrequencyIntervalTlv,
+}
+
+// Get returns first tlv by tag
+func (l TlvList) Get(tag uint32) (Tlv, bool) {
+	for _, tlv := range l {
+		if tlv.Tag == tag {
+			return tlv, true
+		}
+	}
+	return Tlv{}, false
+}
+
+// GetAll returns all tlvs by tag in wire order
+func (l TlvList) GetAll(tag uint32) []Tlv {
+	var tlvs []Tlv
+	for _, tlv := range l {
+		if tlv.Tag == tag {
+			tlvs = append(tlvs, tlv)
+		}
+	}
+	return tlvs
+}
+
+// Add appends tlv value keeping existing tlvs of the same tag
+func (l *TlvList) Add(tag uint32, value []byte) {
+	*l = append(*l, Tlv{Tag: tag, Length: uint32(len(value)), Value: value})
+}
+
+// Set replaces first tlv value by tag and drops duplicates,
+// tlv is appended when absent
+func (l *TlvList) Set(tag uint32, value []byte) {
+	tlv := Tlv{Tag: tag, Length: uint32(len(value)), Value: value}
+	list := (*l)[:0]
+	found := false
+	for _, t := range *l {
+		if t.Tag != tag {
+			list = append(list, t)
+		} else if !found {
+			list = append(list, tlv)
+			found = true
+		}
+	}
+	if !found {
+		list = append(list, tlv)
+	}
+	*l = list
+}
+
+// Del removes all tlvs by tag
+func (l *TlvList) Del(tag uint32) {
+	list := (*l)[:0]
+	for _, t := range *l {
+		if t.Tag != tag {
+			list = append(list, t)
+		}
+	}
+	*l = list
+}
+
+// Int returns integer tlv value
+func (l TlvList) Int(tag uint32) (uint32, error) {
+	tlv, ok := l.Get(tag)
+	if !ok {
+		return 0, ErrEsmeRmissingOptParam
+	}
+	if def, ok := TlvDefs[tag]; ok && def.Kind != TlvInt8 && def.Kind != TlvInt16 && def.Kind != TlvInt32 {
+		return 0, ErrTlvKind
+	}
+	switch len(tlv.Value) {
+	case 1:
+		return uint32(tlv.Value[0]), nil
+	case 2:
+		return uint32(binary.BigEndian.Uint16(tlv.Value)), nil
+	case 4:
+		return binary.BigEndian.Uint32(tlv.Value), nil
+	}
+	return 0, ErrEsmeRinvParLen
+}
+
+// SetInt sets integer tlv value of defined width
+func (l *TlvList) SetInt(tag uint32, v uint32) error {
+	var value []byte
+	switch TlvDefs[tag].Kind {
+	case TlvInt8:
+		if v > 0xFF {
+			return ErrEsmeRinvOptParamVal
+		}
+		value = []byte{byte(v)}
+	case TlvInt16:
+		if v > 0xFFFF {
+			return ErrEsmeRinvOptParamVal
+		}
+		value = make([]byte, 2)
+		binary.BigEndian.PutUint16(value, uint16(v))
+	case TlvInt32:
+		value = make([]byte, 4)
+		binary.BigEndian.PutUint32(value, v)
+	default:
+		return ErrTlvKind
+	}
+	l.Set(tag, value)
+	return nil
+}
+
+// CString returns c-octet string tlv value without terminator
+func (l TlvList) CString(tag uint32) (string, error) {
+	tlv, ok := l.Get(tag)
+	if !ok {
+		return "", ErrEsmeRmissingOptParam
+	}
+	if def, ok := TlvDefs[tag]; ok && def.Kind != TlvCString {
+		return "", ErrTlvKind
+	}
+	if len(tlv.Value) == 0 || tlv.Value[len(tlv.Value)-1] != 0 {
+		return "", ErrEsmeRinvOptParamVal
+	}
+	return string(tlv.Value[:len(tlv.Value)-1]), nil
+}
+
+// SetCString sets c-octet string tlv value, terminator is appended
+func (l *TlvList) SetCString(tag uint32, v string) error {
+	if def, ok := TlvDefs[tag]; ok && def.Kind != TlvCString {
+		return ErrTlvKind
+	}
+	tlv := Tlv{Tag: tag, Value: append([]byte(v), 0)}
+	if err := tlv.validate(); err != nil {
+		return err
+	}
+	l.Set(tag, tlv.Value)
+	return nil
+}
+
+// Bytes returns raw tlv value of any kind
+func (l TlvList) Bytes(tag uint32) ([]byte, error) {
+	tlv, ok := l.Get(tag)
+	if !ok {
+		return nil, ErrEsmeRmissingOptParam
+	}
+	return tlv.Value, nil
+}
+
+// SetBytes sets raw tlv value of octets or composite kind
+func (l *TlvList) SetBytes(tag uint32, v []byte) error {
+	if def, ok := TlvDefs[tag]; ok && def.Kind != TlvOctets && def.Kind != TlvComposite {
+		return ErrTlvKind
+	}
+	tlv := Tlv{Tag: tag, Value: v}
+	if err := tlv.validate(); err != nil {
+		return err
+	}
+	l.Set(tag, v)
+	return nil
+}
+
+// require checks presence of mandatory tlvs
+func (l TlvList) require(tags ...uint32) error {
+	for _, tag := range tags {
+		if _, ok := l.Get(tag); !ok {
+			return ErrEsmeRmissingOptParam
+		}
+	}
+	return nil
+}
+
+// tlvInt reads integer tlv value
+func tlvInt(l TlvList, tag uint32) (uint32, bool) {
+	v, err := l.Int(tag)
+	return v, err == nil
+}
+
+// tlvInt8Int16 reads 1 octet and 2 octet integer pair composite tlv value
+func tlvInt8Int16(l TlvList, tag uint32) (uint32, uint32, bool) {
+	v, err := l.Bytes(tag)
+	if err != nil {
+		return 0, 0, false
+	}
+	return compositeInt8Int16(v)
+}
+
+// compositeInt8Int16 splits 1 octet and 2 octet integer pair
+func compositeInt8Int16(b []byte) (uint32, uint32, bool) {
+	if len(b) != 3 {
+		return 0, 0, false
+	}
+	return uint32(b[0]), uint32(binary.BigEndian.Uint16(b[1:])), true
+}
+
+// setTlvInt8Int16 writes 1 octet and 2 octet integer pair composite tlv value
+func setTlvInt8Int16(l *TlvList, tag uint32, a, b uint32) error {
+	if a > 0xFF || b > 0xFFFF {
+		return ErrEsmeRinvOptParamVal
+	}
+	return l.SetBytes(tag, []byte{byte(a), byte(b >> 8), byte(b)})
+}
+
+// tlvAreaIdentifier reads broadcast area identifier composite tlv value
+func tlvAreaIdentifier(l TlvList, tag uint32) (BroadcastAreaIdentifier, bool) {
+	v, err := l.Bytes(tag)
+	if err != nil || len(v) == 0 {
+		return BroadcastAreaIdentifier{}, false
+	}
+	return BroadcastAreaIdentifier{Format: uint32(v[0]), Details: v[1:]}, true
+}
+
+// MessagePayload returns message_payload tlv value
+func (p *DataSmPdu) MessagePayload() ([]byte, bool) {
+	tlv, ok := p.Tlv.Get(MessagePayloadTlv)
+	return tlv.Value, ok
+}
+
+// SetMessagePayload sets message_payload tlv value
+func (p *DataSmPdu) SetMessagePayload(payload []byte) error {
+	return p.Tlv.SetBytes(MessagePayloadTlv, payload)
+}
+
+// DeliveryFailureReason returns delivery_failure_reason tlv value
+func (p *DataSmRespPdu) DeliveryFailureReason() (uint32, bool) {
+	return tlvInt(p.Tlv, DeliveryFailureReasonTlv)
+}
+
+// NetworkErrorCode returns network_error_code tlv value
+func (p *DataSmRespPdu) NetworkErrorCode() (NetworkErrorCode, bool) {
+	networkType, errorCode, ok := tlvInt8Int16(p.Tlv, NetworkErrorCodeTlv)
+	return NetworkErrorCode{NetworkType: networkType, ErrorCode: errorCode}, ok
+}
+
+// AdditionalStatusInfoText returns additional_status_info_text tlv value
+func (p *DataSmRespPdu) AdditionalStatusInfoText() (string, bool) {
+	v, err := p.Tlv.CString(AdditionalStatusInfoTextTlv)
+	return v, err == nil
+}
+
+// DpfResult returns dpf_result tlv value
+func (p *DataSmRespPdu) DpfResult() (uint32, bool) {
+	return tlvInt(p.Tlv, DpfResultTlv)
+}
+
+// MsAvailabilityStatus returns ms_availability_status tlv value,
+// MsAvailable is the default when tlv is absent
+func (p *AlertNotificationPdu) MsAvailabilityStatus() (uint32, bool) {
+	if v, ok := tlvInt(p.Tlv, MsAvailabilityStatusTlv); ok {
+		return v, true
+	}
+	return MsAvailable, false
+}
+
+// SetMsAvailabilityStatus sets ms_availability_status tlv value
+func (p *AlertNotificationPdu) SetMsAvailabilityStatus(status uint32) error {
+	return p.Tlv.SetInt(MsAvailabilityStatusTlv, status)
+}
+
+// ScInterfaceVersion returns sc_interface_version tlv value
+func (p *BindReceiverRespPdu) ScInterfaceVersion() (uint32, bool) {
+	return tlvInt(p.Tlv, ScInterfaceVersionTlv)
+}
+
+// SetScInterfaceVersion sets sc_interface_version tlv value
+func (p *BindReceiverRespPdu) SetScInterfaceVersion(version uint32) error {
+	return p.Tlv.SetInt(ScInterfaceVersionTlv, version)
+}
+
+// ScInterfaceVersion returns sc_interface_version tlv value
+func (p *BindTransmitterRespPdu) ScInterfaceVersion() (uint32, bool) {
+	return tlvInt(p.Tlv, ScInterfaceVersionTlv)
+}
+
+// SetScInterfaceVersion sets sc_interface_version tlv value
+func (p *BindTransmitterRespPdu) SetScInterfaceVersion(version uint32) error {
+	return p.Tlv.SetInt(ScInterfaceVersionTlv, version)
+}
+
+// ScInterfaceVersion returns sc_interface_version tlv value
+func (p *BindTransceiverRespPdu) ScInterfaceVersion() (uint32, bool) {
+	return tlvInt(p.Tlv, ScInterfaceVersionTlv)
+}
+
+// SetScInterfaceVersion sets sc_interface_version tlv value
+func (p *BindTransceiverRespPdu) SetScInterfaceVersion(version uint32) error {
+	return p.Tlv.SetInt(ScInterfaceVersionTlv, version)
+}
+
+// BroadcastAreaIdentifier returns broadcast_area_identifier tlv value
+func (p *BroadcastSmPdu) BroadcastAreaIdentifier() (BroadcastAreaIdentifier, bool) {
+	return tlvAreaIdentifier(p.Tlv, BroadcastAreaIdentifierTlv)
+}
+
+// SetBroadcastAreaIdentifier sets broadcast_area_identifier tlv value
+func (p *BroadcastSmPdu) SetBroadcastAreaIdentifier(v BroadcastAreaIdentifier) error {
+	return p.Tlv.SetBytes(BroadcastAreaIdentifierTlv, append([]byte{byte(v.Format)}, v.Details...))
+}
+
+// BroadcastContentType returns broadcast_content_type tlv value
+func (p *BroadcastSmPdu) BroadcastContentType() (BroadcastContentType, bool) {
+	networkType, serviceType, ok := tlvInt8Int16(p.Tlv, BroadcastContentTypeTlv)
+	return BroadcastContentType{NetworkType: networkType, ServiceType: serviceType}, ok
+}
+
+// SetBroadcastContentType sets broadcast_content_type tlv value
+func (p *BroadcastSmPdu) SetBroadcastContentType(v BroadcastContentType) error {
+	return setTlvInt8Int16(&p.Tlv, BroadcastContentTypeTlv, v.NetworkType, v.ServiceType)
+}
+
+// BroadcastRepNum returns broadcast_rep_num tlv value
+func (p *BroadcastSmPdu) BroadcastRepNum() (uint32, bool) {
+	return tlvInt(p.Tlv, BroadcastRepNumTlv)
+}
+
+// SetBroadcastRepNum sets broadcast_rep_num tlv value
+func (p *BroadcastSmPdu) SetBroadcastRepNum(num uint32) error {
+	return p.Tlv.SetInt(BroadcastRepNumTlv, num)
+}
+
+// BroadcastFrequencyInterval returns broadcast_frequency_interval tlv value
+func (p *BroadcastSmPdu) BroadcastFrequencyInterval() (BroadcastFrequencyInterval, bool) {
+	unit, value, ok := tlvInt8Int16(p.Tlv, BroadcastFrequencyIntervalTlv)
+	return BroadcastFrequencyInterval{Unit: unit, Value: value}, ok
+}
+
+// SetBroadcastFrequencyInterval sets broadcast_frequency_interval tlv value
+func (p *BroadcastSmPdu) SetBroadcastFrequencyInterval(v BroadcastFrequencyInterval) error {
+	return setTlvInt8Int16(&p.Tlv, BroadcastFrequencyIntervalTlv, v.Unit, v.Value)
+}
+
+// MessageState returns message_state tlv value
+func (p *QueryBroadcastSmRespPdu) MessageState() (uint32, bool) {
+	return tlvInt(p.Tlv, MessageStateTlv)
+}
+
+// BroadcastAreaIdentifier returns broadcast_area_identifier tlv value
+func (p *QueryBroadcastSmRespPdu) BroadcastAreaIdentifier() (BroadcastAreaIdentifier, bool) {
+	return tlvAreaIdentifier(p.Tlv, BroadcastAreaIdentifierTlv)
+}
+
+// BroadcastAreaSuccess returns broadcast_area_success tlv value
+func (p *QueryBroadcastSmRespPdu) BroadcastAreaSuccess() (uint32, bool) {
+	return tlvInt(p.Tlv, BroadcastAreaSuccessTlv)
+}
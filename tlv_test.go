@@ -0,0 +1,244 @@
+package smpp
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestDataSmRespPdu_Tlv(t *testing.T) {
+	req := &DataSmRespPdu{
+		Header: &Header{
+			CommandID:      DataSmResp,
+			CommandStatus:  EsmeRdeliveryFailure,
+			SequenceNumber: 1,
+		},
+		Body: &SmRespBody{MessageID: "abc123"},
+		Tlv:  TlvList{},
+	}
+	req.Tlv.Set(DeliveryFailureReasonTlv, []byte{byte(DeliveryFailureTemporaryNetworkErr)})
+	req.Tlv.Set(NetworkErrorCodeTlv, []byte{byte(NetworkTypeGsm), 0x00, 0x22})
+	req.Tlv.Set(AdditionalStatusInfoTextTlv, []byte("absent subscriber\x00"))
+	req.Tlv.Set(DpfResultTlv, []byte{byte(DpfSet)})
+	buffer := new(bytes.Buffer)
+	if err := NewEncoder(buffer).Encode(req); err != nil {
+		t.Fatal(err)
+	}
+	rep, err := NewDecoder(buffer).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdu, ok := rep.(*DataSmRespPdu)
+	if !ok {
+		t.Fatal()
+	}
+	if v, ok := pdu.DeliveryFailureReason(); !ok || v != DeliveryFailureTemporaryNetworkErr {
+		t.Fatalf("delivery_failure_reason %d %v", v, ok)
+	}
+	if v, ok := pdu.NetworkErrorCode(); !ok || v.NetworkType != NetworkTypeGsm || v.ErrorCode != 0x22 {
+		t.Fatalf("network_error_code %+v %v", v, ok)
+	}
+	if v, ok := pdu.AdditionalStatusInfoText(); !ok || v != "absent subscriber" {
+		t.Fatalf("additional_status_info_text %q %v", v, ok)
+	}
+	if v, ok := pdu.DpfResult(); !ok || v != DpfSet {
+		t.Fatalf("dpf_result %d %v", v, ok)
+	}
+}
+
+func TestDataSmPdu_MessagePayload(t *testing.T) {
+	pdu := &DataSmPdu{Tlv: TlvList{}}
+	if _, ok := pdu.MessagePayload(); ok {
+		t.Fatal("unexpected message_payload")
+	}
+	if err := pdu.SetMessagePayload([]byte{0x00, 0x01}); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := pdu.MessagePayload(); !ok || !bytes.Equal(v, []byte{0x00, 0x01}) {
+		t.Fatalf("message_payload % x %v", v, ok)
+	}
+}
+
+func TestAlertNotificationPdu_MsAvailabilityStatus(t *testing.T) {
+	pdu := &AlertNotificationPdu{Tlv: TlvList{}}
+	if v, ok := pdu.MsAvailabilityStatus(); ok || v != MsAvailable {
+		t.Fatalf("ms_availability_status %d %v", v, ok)
+	}
+	if err := pdu.SetMsAvailabilityStatus(MsUnavailable); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := pdu.MsAvailabilityStatus(); !ok || v != MsUnavailable {
+		t.Fatalf("ms_availability_status %d %v", v, ok)
+	}
+}
+
+func TestBroadcastSmPdu_Tlv(t *testing.T) {
+	req := &BroadcastSmPdu{
+		Header: &Header{
+			CommandID:      BroadcastSm,
+			CommandStatus:  EsmeRok,
+			SequenceNumber: 1,
+		},
+		Body: &BroadcastSmBody{
+			SourceAddrTon: TonAlphanumeric,
+			SourceAddr:    "Alert",
+			MessageID:     "b1",
+			DataCoding:    DataCodingDefault,
+		},
+		Tlv: TlvList{},
+	}
+	buffer := new(bytes.Buffer)
+	if err := NewEncoder(buffer).Encode(req); err != ErrEsmeRmissingOptParam {
+		t.Fatalf("unexpected error %v", err)
+	}
+	area := BroadcastAreaIdentifier{Format: BroadcastAreaFormatAlias, Details: []byte("city")}
+	if err := req.SetBroadcastAreaIdentifier(area); err != nil {
+		t.Fatal(err)
+	}
+	if err := req.SetBroadcastContentType(BroadcastContentType{NetworkType: BroadcastNetworkGsm, ServiceType: 0x0102}); err != nil {
+		t.Fatal(err)
+	}
+	if err := req.SetBroadcastRepNum(3); err != nil {
+		t.Fatal(err)
+	}
+	if err := req.SetBroadcastFrequencyInterval(BroadcastFrequencyInterval{Unit: BroadcastFrequencyMinutes, Value: 15}); err != nil {
+		t.Fatal(err)
+	}
+	buffer = new(bytes.Buffer)
+	if err := NewEncoder(buffer).Encode(req); err != nil {
+		t.Fatal(err)
+	}
+	rep, err := NewDecoder(buffer).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdu, ok := rep.(*BroadcastSmPdu)
+	if !ok {
+		t.Fatal()
+	}
+	if v, ok := pdu.BroadcastAreaIdentifier(); !ok || v.Format != area.Format || !bytes.Equal(v.Details, area.Details) {
+		t.Fatalf("broadcast_area_identifier %+v %v", v, ok)
+	}
+	if v, ok := pdu.BroadcastContentType(); !ok || v.NetworkType != BroadcastNetworkGsm || v.ServiceType != 0x0102 {
+		t.Fatalf("broadcast_content_type %+v %v", v, ok)
+	}
+	if v, ok := pdu.BroadcastRepNum(); !ok || v != 3 {
+		t.Fatalf("broadcast_rep_num %d %v", v, ok)
+	}
+	if v, ok := pdu.BroadcastFrequencyInterval(); !ok || v.Unit != BroadcastFrequencyMinutes || v.Value != 15 {
+		t.Fatalf("broadcast_frequency_interval %+v %v", v, ok)
+	}
+}
+
+func TestBindTransceiverRespPdu_ScInterfaceVersion(t *testing.T) {
+	pdu := &BindTransceiverRespPdu{Tlv: TlvList{}}
+	if err := pdu.SetScInterfaceVersion(NegotiateInterfaceVersion(0x52)); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := pdu.ScInterfaceVersion(); !ok || v != InterfaceVersion50 {
+		t.Fatalf("sc_interface_version %x %v", v, ok)
+	}
+	if v := NegotiateInterfaceVersion(InterfaceVersion34); v != InterfaceVersion34 {
+		t.Fatalf("negotiated %x", v)
+	}
+	if v := NegotiateInterfaceVersion(0x00); v != InterfaceVersion33 {
+		t.Fatalf("negotiated %x", v)
+	}
+}
+
+func TestTlvList_Typed(t *testing.T) {
+	l := TlvList{}
+	if err := l.SetInt(SarMsgRefNumTlv, 0x1234); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.SetInt(SarSegmentSeqnumTlv, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.SetInt(SarTotalSegmentsTlv, 0x100); err != ErrEsmeRinvOptParamVal {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := l.SetInt(MessagePayloadTlv, 1); err != ErrTlvKind {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := l.SetCString(ReceiptedMessageIdTlv, "abc123"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.SetCString(ReceiptedMessageIdTlv, string(make([]byte, 65))); err != ErrEsmeRinvParLen {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := l.SetBytes(NetworkErrorCodeTlv, []byte{0x03, 0x00}); err != ErrEsmeRinvParLen {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if v, err := l.Int(SarMsgRefNumTlv); err != nil || v != 0x1234 {
+		t.Fatalf("sar_msg_ref_num %x %v", v, err)
+	}
+	if v, err := l.Int(SarSegmentSeqnumTlv); err != nil || v != 2 {
+		t.Fatalf("sar_segment_seqnum %d %v", v, err)
+	}
+	if v, err := l.CString(ReceiptedMessageIdTlv); err != nil || v != "abc123" {
+		t.Fatalf("receipted_message_id %q %v", v, err)
+	}
+	if _, err := l.CString(SarMsgRefNumTlv); err != ErrTlvKind {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := l.Int(MessageStateTlv); err != ErrEsmeRmissingOptParam {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestTlvList_Validate(t *testing.T) {
+	pdu := &SubmitSmPdu{
+		Header: &Header{CommandID: SubmitSm, SequenceNumber: 1},
+		Body:   &SmBody{},
+		Tlv:    TlvList{},
+	}
+	pdu.Tlv.Set(SarMsgRefNumTlv, []byte{0x01})
+	if err := NewEncoder(new(bytes.Buffer)).Encode(pdu); err != ErrEsmeRinvParLen {
+		t.Fatalf("unexpected error %v", err)
+	}
+	buffer := bytes.NewBuffer(join(
+		header(40, SubmitSm, 0, 1), cstr(""), []byte{0x00, 0x00}, cstr(""), []byte{0x00, 0x00}, cstr(""),
+		[]byte{0x00, 0x00, 0x00}, cstr(""), cstr(""), []byte{0x00, 0x00, 0x00, 0x00, 0x00},
+		[]byte{0x00, 0x1E, 0x00, 0x03, 'a', 'b', 'c'},
+	))
+	if _, err := NewDecoder(buffer).Decode(); !errors.Is(err, ErrEsmeRinvOptParamVal) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestTlvList_Order(t *testing.T) {
+	wire := join(
+		header(49, SubmitSm, 0, 1), cstr(""), []byte{0x00, 0x00}, cstr(""), []byte{0x00, 0x00}, cstr(""),
+		[]byte{0x00, 0x00, 0x00}, cstr(""), cstr(""), []byte{0x00, 0x00, 0x00, 0x00, 0x00},
+		[]byte{0x14, 0x01, 0x00, 0x01, 0x01},
+		[]byte{0x02, 0x04, 0x00, 0x02, 0x00, 0x07},
+		[]byte{0x14, 0x01, 0x00, 0x01, 0x02},
+	)
+	pdu, err := NewDecoder(bytes.NewBuffer(wire)).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := pdu.(*SubmitSmPdu).Tlv
+	if len(list) != 3 || list[0].Tag != 0x1401 || list[1].Tag != UserMessageReferenceTlv || list[2].Tag != 0x1401 {
+		t.Fatalf("unexpected tlvs %+v", list)
+	}
+	if all := list.GetAll(0x1401); len(all) != 2 || all[0].Value[0] != 0x01 || all[1].Value[0] != 0x02 {
+		t.Fatalf("unexpected vendor tlvs %+v", all)
+	}
+	buffer := new(bytes.Buffer)
+	if err := NewEncoder(buffer).Encode(pdu); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buffer.Bytes(), wire) {
+		t.Fatalf("encoded % x, want % x", buffer.Bytes(), wire)
+	}
+	list.Set(0x1401, []byte{0x03})
+	if len(list) != 2 || list[0].Tag != 0x1401 || list[0].Value[0] != 0x03 {
+		t.Fatalf("unexpected tlvs after set %+v", list)
+	}
+	list.Del(UserMessageReferenceTlv)
+	list.Add(0x1401, []byte{0x04})
+	if all := list.GetAll(0x1401); len(all) != 2 || all[1].Value[0] != 0x04 {
+		t.Fatalf("unexpected tlvs after add %+v", list)
+	}
+}
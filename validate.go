@@ -0,0 +1,699 @@
+package smpp
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrFieldMissing reports missing pdu header or body
+var ErrFieldMissing = errors.New("field is missing")
+
+// ValidationError is pdu field violating spec
+type ValidationError struct {
+	Field string
+	Err   error
+}
+
+// Error implements error
+func (e *ValidationError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+// Unwrap returns underlying error
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors lists all violations of pdu in field order
+type ValidationErrors []*ValidationError
+
+// Error implements error
+func (e ValidationErrors) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "; ")
+}
+
+// Is reports whether any violation matches target
+func (e ValidationErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns first violation
+func (e ValidationErrors) Unwrap() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e[0]
+}
+
+// validator collects violations
+type validator struct {
+	errs ValidationErrors
+	// payload accepts short messages encoder moves to message_payload
+	payload bool
+}
+
+// add records violation
+func (v *validator) add(field string, err error) {
+	v.errs = append(v.errs, &ValidationError{Field: field, Err: err})
+}
+
+// check records violation unless ok
+func (v *validator) check(ok bool, field string, err error) {
+	if !ok {
+		v.add(field, err)
+	}
+}
+
+// result returns collected violations or nil
+func (v *validator) result() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// header checks pdu header presence, command_id field is not checked
+// as encoder writes command id of pdu type
+func (v *validator) header(header *Header) {
+	v.check(header != nil, "header", ErrFieldMissing)
+}
+
+// body checks pdu body presence
+func (v *validator) body(ok bool) bool {
+	v.check(ok, "body", ErrFieldMissing)
+	return ok
+}
+
+// cstring checks c-octet string fits max length including terminator
+func (v *validator) cstring(field string, value string, max int, err error) {
+	v.check(len(value) < max && strings.IndexByte(value, 0) < 0, field, err)
+}
+
+// time checks absolute or relative time format, empty is allowed
+// SMPP v3.4 - 7.1.1 page 133
+func (v *validator) time(field string, value string, err error) {
+	if value == "" {
+		return
+	}
+	ok := len(value) == 16 && strings.IndexByte("+-R", value[15]) >= 0
+	for i := 0; ok && i < 15; i++ {
+		ok = value[i] >= '0' && value[i] <= '9'
+	}
+	v.check(ok, field, err)
+}
+
+// ton checks type of number
+func (v *validator) ton(field string, value uint32, err error) {
+	v.check(value <= TonAbbreviated, field, err)
+}
+
+// npi checks numbering plan indicator
+func (v *validator) npi(field string, value uint32, err error) {
+	switch value {
+	case NpiUnknown, NpiE164, NpiData, NpiTelex, NpiE212, NpiNational, NpiPrivate, NpiErmes, NpiInternet, NpiWapclient:
+		return
+	}
+	v.add(field, err)
+}
+
+// int8 checks 1 octet integer
+func (v *validator) int8(field string, value uint32, err error) {
+	v.check(value <= 0xFF, field, err)
+}
+
+// esmClass checks esm_class message type bits for submitted or delivered messages
+// SMPP v3.4 - 5.2.12 page 121
+func (v *validator) esmClass(value uint32, deliver bool) {
+	ok := value <= 0xFF
+	if deliver {
+		switch value & 0x3C {
+		case EsmSubmitDefault, EsmDeliverSmscReceipt, EsmDeliverSmeAck, EsmDeliverUAck, EsmDeliverConvAbort, EsmDeliverIdn:
+		default:
+			ok = false
+		}
+	} else {
+		switch value & 0x3C {
+		case EsmSubmitDefault, EsmSubmitTypeEsmeDAck, EsmSubmitTypeEsmeUAck:
+		default:
+			ok = false
+		}
+	}
+	v.check(ok, "esm_class", ErrEsmeRinvEsmClass)
+}
+
+// registeredDelivery checks reserved bits and smsc receipt value
+// SMPP v3.4 - 5.2.17 page 124
+func (v *validator) registeredDelivery(value uint32) {
+	v.check(value&^0x1F == 0 && value&0x03 != 0x03, "registered_delivery", ErrEsmeRinvRegDlvFlg)
+}
+
+// shortMessage checks short_message length and sm_length consistency,
+// zero sm_length is left for encoder to derive, overflow tells whether too long
+// message may be moved to message_payload
+func (v *validator) shortMessage(smLength uint32, message []byte, tlvs TlvList, overflow bool) {
+	v.check(len(message) <= MaxShortMessageLength || overflow && len(message) <= 0xFFFF, "short_message", ErrEsmeRinvMsgLen)
+	v.check(smLength == 0 || smLength == uint32(len(message)), "sm_length", ErrEsmeRinvMsgLen)
+	if _, ok := tlvs.Get(MessagePayloadTlv); ok {
+		v.check(len(message) == 0, "message_payload", ErrEsmeRinvOptParamVal)
+	}
+}
+
+// tlvs checks tlv lengths and values
+func (v *validator) tlvs(list TlvList) {
+	for i := range list {
+		if err := list[i].validate(); err != nil {
+			v.add(TlvName(list[i].Tag), err)
+		}
+	}
+}
+
+// bindBody checks bind body
+func (v *validator) bindBody(body *BindBody) {
+	v.cstring("system_id", body.SystemID, 16, ErrEsmeRinvSysId)
+	v.cstring("password", body.Password, 9, ErrEsmeRinvPaswd)
+	v.cstring("system_type", body.SystemType, 13, ErrEsmeRinvSysTyp)
+	v.int8("interface_version", body.InterfaceVersion, ErrEsmeRbindFail)
+	v.ton("addr_ton", body.AddrTon, ErrEsmeRinvSrcTon)
+	v.npi("addr_npi", body.AddrNpi, ErrEsmeRinvSrcNpi)
+	v.cstring("address_range", body.AddressRange, 41, ErrEsmeRbindFail)
+}
+
+// bindRespBody checks bind resp body
+func (v *validator) bindRespBody(body *BindRespBody) {
+	v.cstring("system_id", body.SystemID, 16, ErrEsmeRinvSysId)
+}
+
+// outBindBody checks outbind body
+func (v *validator) outBindBody(body *OutBindBody) {
+	v.cstring("system_id", body.SystemID, 16, ErrEsmeRinvSysId)
+	v.cstring("password", body.Password, 9, ErrEsmeRinvPaswd)
+}
+
+// smBody checks submit_sm or deliver_sm body
+func (v *validator) smBody(body *SmBody, tlvs TlvList, deliver bool) {
+	v.cstring("service_type", body.ServiceType, 6, ErrEsmeRinvSerTyp)
+	v.ton("source_addr_ton", body.SourceAddrTon, ErrEsmeRinvSrcTon)
+	v.npi("source_addr_npi", body.SourceAddrNpi, ErrEsmeRinvSrcNpi)
+	v.cstring("source_addr", body.SourceAddr, 21, ErrEsmeRinvSrcAdr)
+	v.ton("dest_addr_ton", body.DestAddrTon, ErrEsmeRinvDstTon)
+	v.npi("dest_addr_npi", body.DestAddrNpi, ErrEsmeRinvDstNpi)
+	v.cstring("destination_addr", body.DestinationAddr, 21, ErrEsmeRinvDstAdr)
+	v.esmClass(body.EsmClass, deliver)
+	v.int8("protocol_id", body.ProtocolID, ErrEsmeRsubmitFail)
+	v.check(body.PriorityFlag <= PriorityFlag3, "priority_flag", ErrEsmeRinvPrtFlg)
+	v.time("schedule_delivery_time", body.ScheduleDeliveryTime, ErrEsmeRinvSched)
+	v.time("validity_period", body.ValidityPeriod, ErrEsmeRinvExpiry)
+	v.registeredDelivery(body.RegisteredDelivery)
+	v.check(body.ReplaceIfPresentFlag <= ReplaceYes, "replace_if_present_flag", ErrEsmeRinvRepFlag)
+	v.int8("data_coding", body.DataCoding, ErrEsmeRinvDcs)
+	v.int8("sm_default_msg_id", body.SmDefaultMessageID, ErrEsmeRinvDftMsgId)
+	v.shortMessage(body.SmLength, body.ShortMessage, tlvs, v.payload)
+}
+
+// smRespBody checks message id response body
+func (v *validator) smRespBody(body *SmRespBody) {
+	v.cstring("message_id", body.MessageID, 65, ErrEsmeRinvMsgId)
+}
+
+// querySmBody checks query_sm or query_broadcast_sm body
+func (v *validator) querySmBody(body *QuerySmBody) {
+	v.cstring("message_id", body.MessageID, 65, ErrEsmeRinvMsgId)
+	v.ton("source_addr_ton", body.SourceAddrTon, ErrEsmeRinvSrcTon)
+	v.npi("source_addr_npi", body.SourceAddrNpi, ErrEsmeRinvSrcNpi)
+	v.cstring("source_addr", body.SourceAddr, 21, ErrEsmeRinvSrcAdr)
+}
+
+// querySmRespBody checks query_sm_resp body
+func (v *validator) querySmRespBody(body *QuerySmRespBody) {
+	v.cstring("message_id", body.MessageID, 65, ErrEsmeRinvMsgId)
+	v.time("final_date", body.FinalDate, ErrEsmeRqueryFail)
+	v.check(body.MessageState <= StateRejected, "message_state", ErrEsmeRqueryFail)
+	v.int8("error_code", body.ErrorCode, ErrEsmeRqueryFail)
+}
+
+// replaceSmBody checks replace_sm body
+func (v *validator) replaceSmBody(body *ReplaceSmBody) {
+	v.cstring("message_id", body.MessageID, 65, ErrEsmeRinvMsgId)
+	v.ton("source_addr_ton", body.SourceAddrTon, ErrEsmeRinvSrcTon)
+	v.npi("source_addr_npi", body.SourceAddrNpi, ErrEsmeRinvSrcNpi)
+	v.cstring("source_addr", body.SourceAddr, 21, ErrEsmeRinvSrcAdr)
+	v.time("schedule_delivery_time", body.ScheduleDeliveryTime, ErrEsmeRinvSched)
+	v.time("validity_period", body.ValidityPeriod, ErrEsmeRinvExpiry)
+	v.registeredDelivery(body.RegisteredDelivery)
+	v.int8("sm_default_msg_id", body.SmDefaultMessageID, ErrEsmeRinvDftMsgId)
+	v.shortMessage(body.SmLength, body.ShortMessage, nil, false)
+}
+
+// cancelSmBody checks cancel_sm body
+func (v *validator) cancelSmBody(body *CancelSmBody) {
+	v.cstring("service_type", body.ServiceType, 6, ErrEsmeRinvSerTyp)
+	v.cstring("message_id", body.MessageID, 65, ErrEsmeRinvMsgId)
+	v.ton("source_addr_ton", body.SourceAddrTon, ErrEsmeRinvSrcTon)
+	v.npi("source_addr_npi", body.SourceAddrNpi, ErrEsmeRinvSrcNpi)
+	v.cstring("source_addr", body.SourceAddr, 21, ErrEsmeRinvSrcAdr)
+	v.ton("dest_addr_ton", body.DestAddrTon, ErrEsmeRinvDstTon)
+	v.npi("dest_addr_npi", body.DestAddrNpi, ErrEsmeRinvDstNpi)
+	v.cstring("destination_addr", body.DestinationAddr, 21, ErrEsmeRinvDstAdr)
+}
+
+// submitMultiBody checks submit_multi body
+func (v *validator) submitMultiBody(body *SubmitMultiBody, tlvs TlvList) {
+	v.cstring("service_type", body.ServiceType, 6, ErrEsmeRinvSerTyp)
+	v.ton("source_addr_ton", body.SourceAddrTon, ErrEsmeRinvSrcTon)
+	v.npi("source_addr_npi", body.SourceAddrNpi, ErrEsmeRinvSrcNpi)
+	v.cstring("source_addr", body.SourceAddr, 21, ErrEsmeRinvSrcAdr)
+	n := len(body.DestAddresses)
+	v.check(n > 0 && n <= int(MaxNumberOfDests), "number_of_dests", ErrEsmeRinvNumDests)
+	for i := range body.DestAddresses {
+		dest := &body.DestAddresses[i]
+		prefix := "dest_address[" + strconv.Itoa(i) + "]."
+		switch dest.DestFlag {
+		case DestFlagSme:
+			v.ton(prefix+"dest_addr_ton", dest.DestAddrTon, ErrEsmeRinvDstTon)
+			v.npi(prefix+"dest_addr_npi", dest.DestAddrNpi, ErrEsmeRinvDstNpi)
+			v.cstring(prefix+"destination_addr", dest.DestinationAddr, 21, ErrEsmeRinvDstAdr)
+		case DestFlagDistlist:
+			v.cstring(prefix+"dl_name", dest.DlName, 21, ErrEsmeRinvDlName)
+		default:
+			v.add(prefix+"dest_flag", ErrEsmeRinvDestFlag)
+		}
+	}
+	v.esmClass(body.EsmClass, false)
+	v.int8("protocol_id", body.ProtocolID, ErrEsmeRsubmitFail)
+	v.check(body.PriorityFlag <= PriorityFlag3, "priority_flag", ErrEsmeRinvPrtFlg)
+	v.time("schedule_delivery_time", body.ScheduleDeliveryTime, ErrEsmeRinvSched)
+	v.time("validity_period", body.ValidityPeriod, ErrEsmeRinvExpiry)
+	v.registeredDelivery(body.RegisteredDelivery)
+	v.check(body.ReplaceIfPresentFlag <= ReplaceYes, "replace_if_present_flag", ErrEsmeRinvRepFlag)
+	v.int8("data_coding", body.DataCoding, ErrEsmeRinvDcs)
+	v.int8("sm_default_msg_id", body.SmDefaultMessageID, ErrEsmeRinvDftMsgId)
+	v.shortMessage(body.SmLength, body.ShortMessage, tlvs, v.payload)
+}
+
+// submitMultiRespBody checks submit_multi_resp body
+func (v *validator) submitMultiRespBody(body *SubmitMultiRespBody) {
+	v.cstring("message_id", body.MessageID, 65, ErrEsmeRinvMsgId)
+	v.check(len(body.UnsuccessSmes) <= int(MaxNumberOfDests), "no_unsuccess", ErrEsmeRinvNumDests)
+	for i := range body.UnsuccessSmes {
+		sme := &body.UnsuccessSmes[i]
+		prefix := "unsuccess_sme[" + strconv.Itoa(i) + "]."
+		v.ton(prefix+"dest_addr_ton", sme.DestAddrTon, ErrEsmeRinvDstTon)
+		v.npi(prefix+"dest_addr_npi", sme.DestAddrNpi, ErrEsmeRinvDstNpi)
+		v.cstring(prefix+"destination_addr", sme.DestinationAddr, 21, ErrEsmeRinvDstAdr)
+	}
+}
+
+// dataSmBody checks data_sm body
+func (v *validator) dataSmBody(body *DataSmBody) {
+	v.cstring("service_type", body.ServiceType, 6, ErrEsmeRinvSerTyp)
+	v.ton("source_addr_ton", body.SourceAddrTon, ErrEsmeRinvSrcTon)
+	v.npi("source_addr_npi", body.SourceAddrNpi, ErrEsmeRinvSrcNpi)
+	v.cstring("source_addr", body.SourceAddr, 65, ErrEsmeRinvSrcAdr)
+	v.ton("dest_addr_ton", body.DestAddrTon, ErrEsmeRinvDstTon)
+	v.npi("dest_addr_npi", body.DestAddrNpi, ErrEsmeRinvDstNpi)
+	v.cstring("destination_addr", body.DestinationAddr, 65, ErrEsmeRinvDstAdr)
+	// data_sm travels both ways so message types of deliver_sm are accepted too
+	v.esmClass(body.EsmClass, true)
+	v.registeredDelivery(body.RegisteredDelivery)
+	v.int8("data_coding", body.DataCoding, ErrEsmeRinvDcs)
+}
+
+// alertNotificationBody checks alert_notification body
+func (v *validator) alertNotificationBody(body *AlertNotificationBody) {
+	v.ton("source_addr_ton", body.SourceAddrTon, ErrEsmeRinvSrcTon)
+	v.npi("source_addr_npi", body.SourceAddrNpi, ErrEsmeRinvSrcNpi)
+	v.cstring("source_addr", body.SourceAddr, 65, ErrEsmeRinvSrcAdr)
+	v.ton("esme_addr_ton", body.EsmeAddrTon, ErrEsmeRinvDstTon)
+	v.npi("esme_addr_npi", body.EsmeAddrNpi, ErrEsmeRinvDstNpi)
+	v.cstring("esme_addr", body.EsmeAddr, 65, ErrEsmeRinvDstAdr)
+}
+
+// broadcastSmBody checks broadcast_sm body
+func (v *validator) broadcastSmBody(body *BroadcastSmBody, tlvs TlvList) {
+	v.cstring("service_type", body.ServiceType, 6, ErrEsmeRinvSerTyp)
+	v.ton("source_addr_ton", body.SourceAddrTon, ErrEsmeRinvSrcTon)
+	v.npi("source_addr_npi", body.SourceAddrNpi, ErrEsmeRinvSrcNpi)
+	v.cstring("source_addr", body.SourceAddr, 21, ErrEsmeRinvSrcAdr)
+	v.cstring("message_id", body.MessageID, 65, ErrEsmeRinvMsgId)
+	v.check(body.PriorityFlag <= PriorityFlag3, "priority_flag", ErrEsmeRinvPrtFlg)
+	v.time("schedule_delivery_time", body.ScheduleDeliveryTime, ErrEsmeRinvSched)
+	v.time("validity_period", body.ValidityPeriod, ErrEsmeRinvExpiry)
+	v.check(body.ReplaceIfPresentFlag <= ReplaceYes, "replace_if_present_flag", ErrEsmeRinvRepFlag)
+	v.int8("data_coding", body.DataCoding, ErrEsmeRinvDcs)
+	v.int8("sm_default_msg_id", body.SmDefaultMessageID, ErrEsmeRinvDftMsgId)
+	for _, tag := range broadcastSmTlvs {
+		_, ok := tlvs.Get(tag)
+		v.check(ok, TlvName(tag), ErrEsmeRmissingOptParam)
+	}
+}
+
+// cancelBroadcastSmBody checks cancel_broadcast_sm body
+func (v *validator) cancelBroadcastSmBody(body *CancelBroadcastSmBody) {
+	v.cstring("service_type", body.ServiceType, 6, ErrEsmeRinvSerTyp)
+	v.cstring("message_id", body.MessageID, 65, ErrEsmeRinvMsgId)
+	v.ton("source_addr_ton", body.SourceAddrTon, ErrEsmeRinvSrcTon)
+	v.npi("source_addr_npi", body.SourceAddrNpi, ErrEsmeRinvSrcNpi)
+	v.cstring("source_addr", body.SourceAddr, 21, ErrEsmeRinvSrcAdr)
+}
+
+// Validate checks pdu fields against spec
+func (p *BindReceiverPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.bindBody(p.Body)
+	}
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *BindReceiverRespPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.bindRespBody(p.Body)
+	}
+	v.tlvs(p.Tlv)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *BindTransmitterPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.bindBody(p.Body)
+	}
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *BindTransmitterRespPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.bindRespBody(p.Body)
+	}
+	v.tlvs(p.Tlv)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *BindTransceiverPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.bindBody(p.Body)
+	}
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *BindTransceiverRespPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.bindRespBody(p.Body)
+	}
+	v.tlvs(p.Tlv)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *SubmitSmPdu) Validate() error {
+	return p.validate(new(validator))
+}
+
+// validate checks pdu fields collecting violations in v
+func (p *SubmitSmPdu) validate(v *validator) error {
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.smBody(p.Body, p.Tlv, false)
+	}
+	v.tlvs(p.Tlv)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *SubmitSmRespPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.smRespBody(p.Body)
+	}
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *DeliverSmPdu) Validate() error {
+	return p.validate(new(validator))
+}
+
+// validate checks pdu fields collecting violations in v
+func (p *DeliverSmPdu) validate(v *validator) error {
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.smBody(p.Body, p.Tlv, true)
+	}
+	v.tlvs(p.Tlv)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *DeliverSmRespPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.smRespBody(p.Body)
+	}
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *QuerySmPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.querySmBody(p.Body)
+	}
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *QuerySmRespPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.querySmRespBody(p.Body)
+	}
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *ReplaceSmPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.replaceSmBody(p.Body)
+	}
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *ReplaceSmRespPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *CancelSmPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.cancelSmBody(p.Body)
+	}
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *CancelSmRespPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *SubmitMultiPdu) Validate() error {
+	return p.validate(new(validator))
+}
+
+// validate checks pdu fields collecting violations in v
+func (p *SubmitMultiPdu) validate(v *validator) error {
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.submitMultiBody(p.Body, p.Tlv)
+	}
+	v.tlvs(p.Tlv)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *SubmitMultiRespPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.submitMultiRespBody(p.Body)
+	}
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *DataSmPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.dataSmBody(p.Body)
+	}
+	v.tlvs(p.Tlv)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *DataSmRespPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.smRespBody(p.Body)
+	}
+	v.tlvs(p.Tlv)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *AlertNotificationPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.alertNotificationBody(p.Body)
+	}
+	v.tlvs(p.Tlv)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *BroadcastSmPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.broadcastSmBody(p.Body, p.Tlv)
+	}
+	v.tlvs(p.Tlv)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *BroadcastSmRespPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.smRespBody(p.Body)
+	}
+	v.tlvs(p.Tlv)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *QueryBroadcastSmPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.querySmBody(p.Body)
+	}
+	v.tlvs(p.Tlv)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *QueryBroadcastSmRespPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.smRespBody(p.Body)
+	}
+	v.tlvs(p.Tlv)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *CancelBroadcastSmPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.cancelBroadcastSmBody(p.Body)
+	}
+	v.tlvs(p.Tlv)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *CancelBroadcastSmRespPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *EnquireLinkPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *EnquireLinkRespPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *GenericNackPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *UnbindPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *UnbindRespPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	return v.result()
+}
+
+// Validate checks pdu fields against spec
+func (p *OutBindPdu) Validate() error {
+	v := new(validator)
+	v.header(p.Header)
+	if v.body(p.Body != nil) {
+		v.outBindBody(p.Body)
+	}
+	return v.result()
+}
@@ -0,0 +1,129 @@
+package smpp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPdu_ValidateFixtures(t *testing.T) {
+	for _, f := range encoderFixtures {
+		v, ok := f.pdu.(Validator)
+		if !ok {
+			t.Fatalf("%s: no Validate", f.name)
+		}
+		if err := v.Validate(); err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+	}
+}
+
+func TestValidator(t *testing.T) {
+	for id, name := range CommandNames {
+		if _, ok := newPdu(id).(Validator); !ok {
+			t.Fatalf("%s does not implement Validator", name)
+		}
+	}
+}
+
+func TestSubmitSmPdu_Validate(t *testing.T) {
+	pdu := &SubmitSmPdu{
+		Header: &Header{CommandID: SubmitSm, SequenceNumber: 1},
+		Body: &SmBody{
+			SourceAddrTon:      TonAbbreviated + 1,
+			SourceAddr:         strings.Repeat("1", 30),
+			DestAddrNpi:        0x02,
+			EsmClass:           EsmDeliverSmscReceipt,
+			PriorityFlag:       4,
+			ValidityPeriod:     "tomorrow",
+			RegisteredDelivery: 0x03,
+			SmLength:           10,
+			ShortMessage:       make([]byte, 300),
+		},
+	}
+	err := pdu.Validate()
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	fields := []string{
+		"source_addr_ton", "source_addr", "dest_addr_npi", "esm_class", "priority_flag",
+		"validity_period", "registered_delivery", "short_message", "sm_length",
+	}
+	if len(errs) != len(fields) {
+		t.Fatalf("unexpected violations %v", err)
+	}
+	for i, field := range fields {
+		if errs[i].Field != field {
+			t.Fatalf("violation %d is %s, want %s", i, errs[i].Field, field)
+		}
+	}
+	if !errors.Is(err, ErrEsmeRinvSrcAdr) || !errors.Is(err, ErrEsmeRinvMsgLen) || Status(err) != EsmeRinvSrcTon {
+		t.Fatalf("unexpected error %v", err)
+	}
+	pdu.Body = &SmBody{SourceAddr: "sender", DestinationAddr: "79001234567", ShortMessage: []byte("hi")}
+	if err := pdu.Validate(); err != nil {
+		t.Fatalf("sm_length left for encoder: %v", err)
+	}
+}
+
+func TestSubmitMultiPdu_Validate(t *testing.T) {
+	pdu := &SubmitMultiPdu{
+		Header: &Header{CommandID: SubmitMulti},
+		Body: &SubmitMultiBody{
+			DestAddresses: []DestAddress{
+				{DestFlag: DestFlagSme, DestinationAddr: "79001234567"},
+				{DestFlag: 3},
+			},
+		},
+	}
+	err := pdu.Validate()
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "dest_address[1].dest_flag" {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := (&EnquireLinkPdu{}).Validate(); !errors.Is(err, ErrFieldMissing) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestEncoder_Validate(t *testing.T) {
+	pdu := &SubmitSmPdu{
+		Header: &Header{CommandID: SubmitSm, SequenceNumber: 1},
+		Body:   &SmBody{SourceAddr: "sender", DestinationAddr: "79001234567", ShortMessage: make([]byte, 300)},
+	}
+	encoder := NewEncoder(nil)
+	if err := encoder.Validate(pdu); !errors.Is(err, ErrEsmeRinvMsgLen) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	encoder.SetMessagePayload(true)
+	if err := encoder.Validate(pdu); err != nil {
+		t.Fatalf("message moved to message_payload flagged: %v", err)
+	}
+	if _, err := encoder.Append(nil, pdu); err != nil {
+		t.Fatal(err)
+	}
+	encoder.SetVersion(InterfaceVersion33)
+	if err := encoder.Validate(pdu); !errors.Is(err, ErrEsmeRinvMsgLen) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := encoder.Validate(&EnquireLinkPdu{}); !errors.Is(err, ErrFieldMissing) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestDataSmPdu_Validate(t *testing.T) {
+	pdu := &DataSmPdu{
+		Header: &Header{CommandID: DataSm},
+		Body:   &DataSmBody{EsmClass: EsmDeliverSmscReceipt},
+	}
+	if err := pdu.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	pdu.Body.EsmClass = 0x3C
+	err := pdu.Validate()
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "esm_class" {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -1,25 +1,33 @@
 package smpp
 
 import (
+	"bufio"
 	"bytes"
 	"io"
-	"net"
 )
 
 // Writer writes pdu buffer
 type Writer struct {
-	conn *net.TCPConn
+	w  io.Writer
+	bw *bufio.Writer
 }
 
-// NewWriter writer constructor
-func NewWriter(conn *net.TCPConn) *Writer {
-	return &Writer{conn: conn}
+// NewWriter writer constructor, writes pdus straight to w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// NewWriterSize buffered writer constructor, Flush must be called
+// to push buffered pdus to w
+func NewWriterSize(w io.Writer, size int) *Writer {
+	bw := bufio.NewWriterSize(w, size)
+	return &Writer{w: bw, bw: bw}
 }
 
 // Write write pdu
 func (w *Writer) Write(buffer *bytes.Buffer) error {
 	l := buffer.Len()
-	n, err := w.conn.Write(buffer.Bytes())
+	n, err := w.w.Write(buffer.Bytes())
 	if err != nil {
 		return err
 	}
@@ -28,3 +36,11 @@ func (w *Writer) Write(buffer *bytes.Buffer) error {
 	}
 	return nil
 }
+
+// Flush writes buffered pdus to underlying writer
+func (w *Writer) Flush() error {
+	if w.bw == nil {
+		return nil
+	}
+	return w.bw.Flush()
+}